@@ -40,7 +40,7 @@ func setUpServer(db *database.Database) *echo.Echo {
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
-		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete},
+		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete},
 	}))
 
 	addRoutes(e, *db)
@@ -59,8 +59,98 @@ func addRoutes(e *echo.Echo, db database.Database) {
 	e.GET(internal.RouteCalendar, calendarAPI.GetCalendarHandler)
 	e.POST(internal.RouteCalendar, calendarAPI.PostCalendarHandler)
 	e.PUT(internal.RouteCalendar, calendarAPI.PutCalendarHandler)
+	e.PATCH(internal.RouteCalendar, calendarAPI.PatchCalendarHandler)
 	e.DELETE(internal.RouteCalendar, calendarAPI.DeleteCalendarHandler)
 
 	e.PUT(internal.RouteCalendarRedo, calendarAPI.RedoCalendarHandler)
 	e.PUT(internal.RouteCalendarRedoWeek, calendarAPI.RedoWeekCalendarHandler)
+	e.POST(internal.RouteCalendarCopy, calendarAPI.CopyWeekCalendarHandler)
+	e.POST(internal.RouteCalendarImport, calendarAPI.ImportCalendarHandler)
+	e.POST(internal.RouteCalendarCSV, calendarAPI.ImportCalendarCSVHandler)
+
+	templateAPI := handlers.TemplateAPI{DB: db, Manager: managers.NewTemplateManager(db), CalendarManager: calendarManager}
+	e.GET(internal.RouteTemplates, templateAPI.GetTemplatesHandler)
+	e.POST(internal.RouteTemplates, templateAPI.PostTemplateHandler)
+	e.GET(internal.RouteTemplate, templateAPI.GetTemplateHandler)
+	e.PUT(internal.RouteTemplate, templateAPI.PutTemplateHandler)
+	e.DELETE(internal.RouteTemplate, templateAPI.DeleteTemplateHandler)
+	e.POST(internal.RouteTemplateApply, templateAPI.ApplyTemplateHandler)
+
+	ruleAPI := handlers.RuleAPI{DB: db, Manager: managers.NewRuleManager(db)}
+	e.GET(internal.RouteRules, ruleAPI.GetRulesHandler)
+	e.POST(internal.RouteRules, ruleAPI.PostRuleHandler)
+	e.PUT(internal.RouteRule, ruleAPI.PutRuleHandler)
+	e.DELETE(internal.RouteRule, ruleAPI.DeleteRuleHandler)
+
+	themeAPI := handlers.ThemeAPI{DB: db, Manager: managers.NewThemeManager(db)}
+	e.GET(internal.RouteThemes, themeAPI.GetThemesHandler)
+	e.POST(internal.RouteThemes, themeAPI.PostThemeHandler)
+	e.PUT(internal.RouteTheme, themeAPI.PutThemeHandler)
+	e.DELETE(internal.RouteTheme, themeAPI.DeleteThemeHandler)
+
+	exclusionAPI := handlers.ExclusionAPI{DB: db, Manager: managers.NewExclusionManager(db)}
+	e.GET(internal.RouteExclusions, exclusionAPI.GetExclusionsHandler)
+	e.POST(internal.RouteExclusions, exclusionAPI.PostExclusionHandler)
+	e.PUT(internal.RouteExclusion, exclusionAPI.PutExclusionHandler)
+	e.DELETE(internal.RouteExclusion, exclusionAPI.DeleteExclusionHandler)
+
+	specialDateAPI := handlers.SpecialDateAPI{DB: db, Manager: managers.NewSpecialDateManager(db)}
+	e.GET(internal.RouteSpecialDates, specialDateAPI.GetSpecialDatesHandler)
+	e.POST(internal.RouteSpecialDates, specialDateAPI.PostSpecialDateHandler)
+	e.POST(internal.RouteSpecialDateICS, specialDateAPI.ImportSpecialDatesHandler)
+	e.DELETE(internal.RouteSpecialDate, specialDateAPI.DeleteSpecialDateHandler)
+
+	typePolicyAPI := handlers.TypePolicyAPI{DB: db, Manager: managers.NewTypePolicyManager(db)}
+	e.GET(internal.RouteTypePolicies, typePolicyAPI.GetTypePoliciesHandler)
+	e.PUT(internal.RouteTypePolicy, typePolicyAPI.PutTypePolicyHandler)
+	e.DELETE(internal.RouteTypePolicy, typePolicyAPI.DeleteTypePolicyHandler)
+
+	mealYieldAPI := handlers.MealYieldAPI{DB: db, Manager: managers.NewMealYieldManager(db)}
+	e.GET(internal.RouteMealYields, mealYieldAPI.GetMealYieldsHandler)
+	e.PUT(internal.RouteMealYield, mealYieldAPI.PutMealYieldHandler)
+	e.DELETE(internal.RouteMealYield, mealYieldAPI.DeleteMealYieldHandler)
+
+	shoppingListAPI := handlers.ShoppingListAPI{DB: db, Manager: managers.NewShoppingListManager(db)}
+	e.GET(internal.RouteShoppingList, shoppingListAPI.GetShoppingListHandler)
+
+	pantryAPI := handlers.PantryAPI{DB: db, Manager: managers.NewPantryManager(db)}
+	e.GET(internal.RoutePantry, pantryAPI.GetPantryItemsHandler)
+	e.POST(internal.RoutePantry, pantryAPI.PostPantryItemHandler)
+	e.PUT(internal.RoutePantryItem, pantryAPI.PutPantryItemHandler)
+	e.DELETE(internal.RoutePantryItem, pantryAPI.DeletePantryItemHandler)
+
+	mealCostAPI := handlers.MealCostAPI{DB: db, Manager: managers.NewMealCostManager(db)}
+	e.GET(internal.RouteMealCosts, mealCostAPI.GetMealCostsHandler)
+	e.PUT(internal.RouteMealCost, mealCostAPI.PutMealCostHandler)
+	e.DELETE(internal.RouteMealCost, mealCostAPI.DeleteMealCostHandler)
+
+	ingredientPriceAPI := handlers.IngredientPriceAPI{DB: db, Manager: managers.NewIngredientPriceManager(db)}
+	e.GET(internal.RouteIngredientPrices, ingredientPriceAPI.GetIngredientPricesHandler)
+	e.POST(internal.RouteIngredientPrices, ingredientPriceAPI.PostIngredientPriceHandler)
+	e.PUT(internal.RouteIngredientPrice, ingredientPriceAPI.PutIngredientPriceHandler)
+	e.DELETE(internal.RouteIngredientPrice, ingredientPriceAPI.DeleteIngredientPriceHandler)
+
+	icsAPI := handlers.ICSAPI{DB: db, Manager: managers.NewICSManager(db)}
+	e.GET(internal.RouteCalendarICS, icsAPI.GetCalendarICSHandler)
+
+	feedAPI := handlers.FeedAPI{DB: db, Manager: managers.NewFeedManager(db)}
+	e.POST(internal.RouteFeedToken, feedAPI.PostFeedTokenHandler)
+	e.PUT(internal.RouteFeedToken, feedAPI.PutFeedTokenHandler)
+	e.DELETE(internal.RouteFeedToken, feedAPI.DeleteFeedTokenHandler)
+	e.GET(internal.RouteFeed, feedAPI.GetFeedHandler)
+
+	printAPI := handlers.PrintAPI{DB: db, Manager: managers.NewPrintManager(db)}
+	e.GET(internal.RouteCalendarPrint, printAPI.GetCalendarPrintHandler)
+
+	calDAVAPI := handlers.CalDAVAPI{DB: db, Manager: managers.NewCalDAVManager(db)}
+	e.Add(echo.PROPFIND, internal.RouteCalDAV, calDAVAPI.PropfindHandler)
+	e.Add(echo.REPORT, internal.RouteCalDAV, calDAVAPI.ReportHandler)
+	e.Add(echo.PROPFIND, internal.RouteCalDAVEvent, calDAVAPI.PropfindHandler)
+	e.GET(internal.RouteCalDAVEvent, calDAVAPI.GetEventHandler)
+	e.PUT(internal.RouteCalDAVEvent, calDAVAPI.PutEventHandler)
+
+	settingsAPI := handlers.SettingsAPI{DB: db, Manager: managers.NewSettingsManager(db)}
+	e.GET(internal.RouteSettings, settingsAPI.GetSettingsHandler)
+	e.PUT(internal.RouteSettings, settingsAPI.PutSettingsHandler)
+	e.PATCH(internal.RouteSettings, settingsAPI.PatchSettingsHandler)
 }
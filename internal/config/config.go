@@ -19,6 +19,9 @@ type Configuration struct {
 	UsersURL string `mapstructure:"USERS_URL" json:"UsersURL" default:"0.0.0.0:3100"`
 	// MealsURL --> URL of the meals microservice
 	MealsURL string `mapstructure:"MEALS_URL" json:"MealsURL" default:"0.0.0.0:3200"`
+	// Seasons --> Season of every month in the northern hemisphere, as
+	// "season:month,month;season:month". Default the calendar quarters
+	Seasons string `mapstructure:"SEASONS" json:"Seasons" default:"invierno:1,2,3;primavera:4,5,6;verano:7,8,9;otoño:10,11,12"`
 }
 
 func LoadConfiguration() error {
@@ -31,6 +34,7 @@ func LoadConfiguration() error {
 	Config.DBName = os.Getenv("DB_NAME")
 	Config.UsersURL = os.Getenv("USERS_URL")
 	Config.MealsURL = os.Getenv("MEALS_URL")
+	Config.Seasons = os.Getenv("SEASONS")
 
 	return nil
 }
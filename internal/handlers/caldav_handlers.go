@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/pkg/caldav"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+const davCapabilities = "1, calendar-access"
+
+type CalDAVAPI struct {
+	DB      database.Database
+	Manager managers.ICalDAVManager
+}
+
+// PropfindHandler returns the properties of the collection of the user, or of
+// one of its events, as a multistatus.
+func (a *CalDAVAPI) PropfindHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	request, err := caldav.ParseRequest(c.Request().Body)
+	if err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	responses, err := a.Manager.Propfind(userID, c.Param(internal.ParamEvent), c.Request().Header.Get("Depth"), request)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return multistatus(c, responses)
+}
+
+// ReportHandler answers the calendar-query and calendar-multiget reports of the
+// collection of the user.
+func (a *CalDAVAPI) ReportHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	request, err := caldav.ParseRequest(c.Request().Body)
+	if err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	responses, err := a.Manager.Report(userID, request)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return multistatus(c, responses)
+}
+
+func (a *CalDAVAPI) GetEventHandler(c echo.Context) error {
+	var userID, name string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamEvent:  {Target: &name, Err: internal.ErrEventNotFound},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	event, err := a.Manager.GetEvent(userID, name)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	c.Response().Header().Set("DAV", davCapabilities)
+	c.Response().Header().Set("ETag", `"`+event.ETag+`"`)
+	return c.Blob(http.StatusOK, mimeTextCalendar, event.Data)
+}
+
+// PutEventHandler changes the meal of the day of the event, honouring the
+// If-Match and If-None-Match headers, and returns the new ETag.
+func (a *CalDAVAPI) PutEventHandler(c echo.Context) error {
+	var userID, name string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamEvent:  {Target: &name, Err: internal.ErrEventNotFound},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	header := c.Request().Header
+	event, err := a.Manager.PutEvent(userID, name, c.Request().Body, header.Get("If-Match"), header.Get("If-None-Match"))
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	c.Response().Header().Set("DAV", davCapabilities)
+	c.Response().Header().Set("ETag", `"`+event.ETag+`"`)
+	return c.NoContent(http.StatusNoContent)
+}
+
+func multistatus(c echo.Context, responses []caldav.Response) error {
+	var b bytes.Buffer
+	if err := caldav.WriteMultistatus(&b, responses); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrSomethingWentWrong)
+	}
+	c.Response().Header().Set("DAV", davCapabilities)
+	return c.Blob(http.StatusMultiStatus, echo.MIMEApplicationXMLCharsetUTF8, b.Bytes())
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"context"
+	"encoding/xml"
+	goical "github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+type davMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Status   string `xml:"DAV: status"`
+		Propstat []struct {
+			Prop struct {
+				ETag         string `xml:"DAV: getetag"`
+				CTag         string `xml:"http://calendarserver.org/ns/ getctag"`
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+				Inner        string `xml:",innerxml"`
+			} `xml:"DAV: prop"`
+			Status string `xml:"DAV: status"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+func (s *CalendarAPITestSuite) TestCalDAVHandlers() {
+	userID := "01FN3EEB2NVFJAHAPU00000022"
+	lentils := &models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002201", Name: "Lentejas con chorizo", Kcal: 600}
+	chicken := &models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002202", Name: "Pollo asado", Kcal: 700}
+	pasta := &models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002203", Name: "Macarrones con tomate", Kcal: 500}
+	monday := currentMonday()
+	date := func(days int) string { return monday.AddDate(0, 0, days).Format("2006/01/02") }
+	event := func(days int) string { return monday.AddDate(0, 0, days).Format("20060102") }
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(windowCalendar(userID,
+		models.Calendar{UserId: userID, MealId: lentils.Id, Name: lentils.Name, Date: date(0), Servings: 2},
+		models.Calendar{UserId: userID, MealId: lentils.Id, Name: lentils.Name, Date: date(1), Servings: 2, LeftoverOf: date(0)},
+		models.Calendar{UserId: userID, MealId: chicken.Id, Name: chicken.Name, Date: date(3), Servings: 2},
+	)))
+	api := CalDAVAPI{DB: *s.db, Manager: managers.NewCalDAVManager(*s.db)}
+	e := echo.New()
+	e.Add(echo.PROPFIND, internal.RouteCalDAV, api.PropfindHandler)
+	e.Add(echo.REPORT, internal.RouteCalDAV, api.ReportHandler)
+	e.Add(echo.PROPFIND, internal.RouteCalDAVEvent, api.PropfindHandler)
+	e.GET(internal.RouteCalDAVEvent, api.GetEventHandler)
+	e.PUT(internal.RouteCalDAVEvent, api.PutEventHandler)
+	collection := "/caldav/" + userID + "/"
+	do := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	parse := func(rec *httptest.ResponseRecorder) davMultistatus {
+		s.Equal(http.StatusMultiStatus, rec.Code)
+		s.Equal("1, calendar-access", rec.Header().Get("DAV"))
+		var multistatus davMultistatus
+		s.NoError(xml.Unmarshal(rec.Body.Bytes(), &multistatus))
+		return multistatus
+	}
+
+	rec := do(echo.PROPFIND, collection, `<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/">
+  <D:prop><D:resourcetype/><CS:getctag/><D:owner/></D:prop></D:propfind>`, map[string]string{"Depth": "0"})
+	multistatus := parse(rec)
+	s.Len(multistatus.Responses, 1)
+	s.Equal(collection, multistatus.Responses[0].Href)
+	s.Len(multistatus.Responses[0].Propstat, 2)
+	s.Contains(multistatus.Responses[0].Propstat[0].Prop.Inner, `<calendar xmlns="urn:ietf:params:xml:ns:caldav">`)
+	ctag := multistatus.Responses[0].Propstat[0].Prop.CTag
+	s.NotEmpty(ctag)
+	s.Equal("HTTP/1.1 404 Not Found", multistatus.Responses[0].Propstat[1].Status, "owner is not supported")
+
+	multistatus = parse(do(echo.PROPFIND, collection, "", map[string]string{"Depth": "1"}))
+	s.Len(multistatus.Responses, 4, "the collection and the days with a meal")
+	s.Equal(collection+event(0)+".ics", multistatus.Responses[1].Href)
+	s.Empty(multistatus.Responses[1].Propstat[0].Prop.CalendarData, "allprop does not send the data")
+
+	multistatus = parse(do(echo.REPORT, collection, `<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">
+    <C:time-range start="`+event(1)+`T000000Z" end="`+event(3)+`T000000Z"/>
+  </C:comp-filter></C:comp-filter></C:filter></C:calendar-query>`, nil))
+	s.Len(multistatus.Responses, 1)
+	s.Equal(collection+event(1)+".ics", multistatus.Responses[0].Href)
+	leftovers := multistatus.Responses[0].Propstat[0].Prop
+	s.Contains(leftovers.CalendarData, "SUMMARY:Lentejas con chorizo (sobras)\r\n")
+	s.NotContains(leftovers.CalendarData, "METHOD:")
+
+	multistatus = parse(do(echo.REPORT, collection, `<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/></D:prop><D:href>`+collection+event(3)+`.ics</D:href><D:href>`+collection+event(2)+`.ics</D:href>
+</C:calendar-multiget>`, nil))
+	s.Len(multistatus.Responses, 2)
+	etag := multistatus.Responses[0].Propstat[0].Prop.ETag
+	s.Equal("HTTP/1.1 404 Not Found", multistatus.Responses[1].Status)
+
+	rec = do(http.MethodGet, collection+event(3)+".ics", "", nil)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(etag, rec.Header().Get("ETag"))
+	s.Contains(rec.Body.String(), "SUMMARY:Pollo asado\r\n")
+	s.Equal(http.StatusNotFound, do(http.MethodGet, collection+event(2)+".ics", "", nil).Code)
+
+	edited := strings.Replace(rec.Body.String(), "SUMMARY:Pollo asado", "SUMMARY:Macarrones con tomate", 1)
+	rec = do(http.MethodPut, collection+event(3)+".ics", edited, map[string]string{"If-Match": `"0123"`})
+	s.Equal(http.StatusPreconditionFailed, rec.Code)
+	s.Equal(http.StatusPreconditionFailed, do(http.MethodPut, collection+event(3)+".ics", edited, map[string]string{"If-None-Match": "*"}).Code)
+
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return([]*models.MealToFront{lentils, chicken, pasta}, nil).Once()
+	s.httpMock.On("GetMeal", userID, pasta.Id).Return(*pasta, nil).Once()
+	rec = do(http.MethodPut, collection+event(3)+".ics", edited, map[string]string{"If-Match": etag})
+	s.Equal(http.StatusNoContent, rec.Code)
+	s.NotEqual(etag, rec.Header().Get("ETag"))
+	day, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendarSpecificDate(userID, date(3))
+	s.NoError(err)
+	s.Equal(pasta.Id, day[0].MealId)
+
+	rec = do(http.MethodPut, collection+event(1)+".ics", leftovers.CalendarData, map[string]string{"If-Match": leftovers.ETag})
+	s.Equal(http.StatusNoContent, rec.Code, "an unchanged meal keeps the leftovers")
+	s.Equal(leftovers.ETag, rec.Header().Get("ETag"))
+
+	multistatus = parse(do(echo.PROPFIND, collection, "", map[string]string{"Depth": "0"}))
+	s.NotEqual(ctag, multistatus.Responses[0].Propstat[0].Prop.CTag)
+}
+
+// TestCalDAVClient syncs the calendar with the CalDAV client of go-webdav, run
+// against the routes as registered by the server.
+func (s *CalendarAPITestSuite) TestCalDAVClient() {
+	userID := "01FN3EEB2NVFJAHAPU00000030"
+	lentils := &models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00003001", Name: "Lentejas con chorizo", Kcal: 600}
+	pasta := &models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00003002", Name: "Macarrones con tomate", Kcal: 500}
+	monday := currentMonday()
+	date := func(days int) string { return monday.AddDate(0, 0, days).Format("2006/01/02") }
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(windowCalendar(userID,
+		models.Calendar{UserId: userID, MealId: lentils.Id, Name: lentils.Name, Date: date(0), Servings: 2},
+		models.Calendar{UserId: userID, MealId: lentils.Id, Name: lentils.Name, Date: date(1), Servings: 2, LeftoverOf: date(0)},
+		models.Calendar{UserId: userID, MealId: lentils.Id, Name: lentils.Name, Date: date(16), Servings: 2},
+	)))
+	api := CalDAVAPI{DB: *s.db, Manager: managers.NewCalDAVManager(*s.db)}
+	e := echo.New()
+	e.Add(echo.PROPFIND, internal.RouteCalDAV, api.PropfindHandler)
+	e.Add(echo.REPORT, internal.RouteCalDAV, api.ReportHandler)
+	e.Add(echo.PROPFIND, internal.RouteCalDAVEvent, api.PropfindHandler)
+	e.GET(internal.RouteCalDAVEvent, api.GetEventHandler)
+	e.PUT(internal.RouteCalDAVEvent, api.PutEventHandler)
+	server := httptest.NewServer(e)
+	defer server.Close()
+	client, err := caldav.NewClient(server.Client(), server.URL)
+	s.NoError(err)
+	ctx := context.Background()
+	collection := "/caldav/" + userID + "/"
+
+	calendars, err := client.FindCalendars(ctx, collection)
+	s.NoError(err)
+	s.Len(calendars, 1)
+	s.Equal(collection, calendars[0].Path)
+	s.Contains(calendars[0].SupportedComponentSet, goical.CompEvent)
+
+	objects, err := client.QueryCalendar(ctx, collection, &caldav.CalendarQuery{
+		CompRequest: caldav.CalendarCompRequest{Name: goical.CompCalendar, AllProps: true, AllComps: true},
+		CompFilter: caldav.CompFilter{Name: goical.CompCalendar, Comps: []caldav.CompFilter{{
+			Name:  goical.CompEvent,
+			Start: time.Date(monday.Year(), monday.Month(), monday.Day()-3, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(monday.Year(), monday.Month(), monday.Day()+4, 0, 0, 0, 0, time.UTC),
+		}}},
+	})
+	s.NoError(err)
+	s.Len(objects, 2, "the days with a meal in the range")
+	var summaries []string
+	for _, object := range objects {
+		s.NotEmpty(object.ETag)
+		events := object.Data.Events()
+		s.Len(events, 1)
+		summary, err := events[0].Props.Text(goical.PropSummary)
+		s.NoError(err)
+		summaries = append(summaries, summary)
+	}
+	s.ElementsMatch([]string{"Lentejas con chorizo", "Lentejas con chorizo (sobras)"}, summaries)
+
+	event := collection + monday.Format("20060102") + ".ics"
+	object, err := client.GetCalendarObject(ctx, event)
+	s.NoError(err)
+	object.Data.Events()[0].Props.SetText(goical.PropSummary, pasta.Name)
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return([]*models.MealToFront{lentils, pasta}, nil)
+	s.httpMock.On("GetMeal", userID, pasta.Id).Return(*pasta, nil).Once()
+	put, err := client.PutCalendarObject(ctx, event, object.Data)
+	s.NoError(err)
+	s.NotEqual(object.ETag, put.ETag)
+
+	object, err = client.GetCalendarObject(ctx, event)
+	s.NoError(err)
+	summary, err := object.Data.Events()[0].Props.Text(goical.PropSummary)
+	s.NoError(err)
+	s.Equal(pasta.Name, summary)
+	s.Equal(put.ETag, object.ETag)
+}
+
+func (s *CalendarAPITestSuite) TestCalDAVHandlersMoveCalendar() {
+	userID := "01FN3EEB2NVFJAHAPU00000034"
+	lastMonday := currentMonday().AddDate(0, 0, -7)
+	var calendar []models.Calendar
+	for i := 0; i < 28; i++ {
+		calendar = append(calendar, models.Calendar{UserId: userID, MealId: mealsDb[i%len(mealsDb)].Id, Name: mealsDb[i%len(mealsDb)].Name, Date: lastMonday.AddDate(0, 0, i).Format("2006/01/02")})
+	}
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(calendar))
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(mealsDb, nil).Once()
+	api := CalDAVAPI{DB: *s.db, Manager: managers.NewCalDAVManager(*s.db)}
+	e := echo.New()
+	e.Add(echo.PROPFIND, internal.RouteCalDAV, api.PropfindHandler)
+	collection := "/caldav/" + userID + "/"
+
+	req := httptest.NewRequest(echo.PROPFIND, collection, nil)
+	req.Header.Set("Depth", "1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	s.Equal(http.StatusMultiStatus, rec.Code)
+	s.NotContains(rec.Body.String(), collection+lastMonday.Format("20060102")+".ics", "the past week is not listed")
+	s.Contains(rec.Body.String(), collection+currentMonday().AddDate(0, 0, 27).Format("20060102")+".ics")
+	s.requireWindow(userID)
+}
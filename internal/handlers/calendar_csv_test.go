@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestCalendarCSVHandlers() {
+	userID := "01FN3EEB2NVFJAHAPU00000023"
+	lentils := models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002301", Name: "Lentejas, con chorizo", Type: "semanal", Kcal: 600}
+	chicken := models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002302", Name: "Pollo asado", Type: "normal", Kcal: 700}
+	pasta := models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002303", Name: "Macarrones", Type: "normal", Kcal: 500}
+	missingID := "01FN3EEB2NVFJAHAPM00002399"
+	today := time.Now()
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	date := func(days int) string { return monday.AddDate(0, 0, days).Format("2006/01/02") }
+	calendar := []models.Calendar{
+		{UserId: userID, MealId: lentils.Id, Name: lentils.Name, Date: date(0), Kcal: 600, Servings: 3},
+		{UserId: userID, MealId: "", Name: models.NoMeal, Date: date(1), Servings: 2},
+		{UserId: userID, MealId: chicken.Id, Name: chicken.Name, Date: date(2), Kcal: 700, Servings: 2},
+	}
+	calendar = append(calendar, models.Calendar{UserId: userID, Name: models.NoMeal, Date: date(3), Servings: 2},
+		models.Calendar{UserId: userID, MealId: chicken.Id, Name: chicken.Name, Date: date(4), Kcal: 700, Servings: 2})
+	for i := 5; i < 28; i++ {
+		calendar = append(calendar, models.Calendar{UserId: userID, Name: models.NoMeal, Date: date(i), Servings: 2})
+	}
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(calendar))
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+
+	s.httpMock.On("GetMeal", userID, lentils.Id).Return(lentils, nil).Once()
+	s.httpMock.On("GetMeal", userID, chicken.Id).Return(chicken, nil).Once()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, internal.RouteCalendar, nil)
+	req.Header.Set(echo.HeaderAccept, "text/csv")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+	s.NoError(api.GetCalendarHandler(c))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("text/csv; charset=UTF-8", rec.Header().Get(echo.HeaderContentType))
+	s.True(strings.HasPrefix(rec.Body.String(), "date,meal_id,name,type,kcal\n"+
+		date(0)+","+lentils.Id+",\"Lentejas, con chorizo\",semanal,600\n"+
+		date(1)+",,NO MEAL,,0\n"+
+		date(2)+","+chicken.Id+",Pollo asado,normal,700\n"), rec.Body.String())
+	s.Equal(29, strings.Count(rec.Body.String(), "\n"), "the 28 days of the calendar")
+
+	importCSV := func(body *bytes.Buffer, contentType string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, internal.RouteCalendarCSV, body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		return c, rec
+	}
+	s.httpMock.On("GetMeal", userID, pasta.Id).Return(pasta, nil).Once()
+	s.httpMock.On("GetMeal", userID, missingID).Return(models.MealToFront{}, internal.ErrMealNotFound).Once()
+	form := &bytes.Buffer{}
+	writer := multipart.NewWriter(form)
+	file, err := writer.CreateFormFile("file", "calendar.csv")
+	s.NoError(err)
+	_, err = file.Write([]byte("\xef\xbb\xbfmeal_id;date;name\n" +
+		pasta.Id + ";" + date(0) + ";Macarrones\n" +
+		lentils.Id + ";01/04/2030;Lentejas\n" +
+		missingID + ";" + date(2) + ";Pizza\n" +
+		chicken.Id + ";" + date(0) + ";Pollo\n" +
+		";" + date(4) + ";\n" +
+		pasta.Id + ";" + date(-1) + ";Macarrones\n" +
+		pasta.Id + ";2040/01/01;Macarrones\n"))
+	s.NoError(err)
+	s.NoError(writer.Close())
+	c, rec = importCSV(form, writer.FormDataContentType())
+	s.NoError(api.ImportCalendarCSVHandler(c))
+	s.Equal(http.StatusOK, rec.Code)
+	var report models.CalendarCSVImport
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), &report))
+	s.Equal(1, report.Imported)
+	s.Equal([]models.CSVRowError{
+		{Row: 3, Date: "01/04/2030", MealId: lentils.Id, Message: internal.ErrInvalidDateFormat.Error()},
+		{Row: 4, Date: date(2), MealId: missingID, Message: internal.ErrMealNotFound.Error()},
+		{Row: 5, Date: date(0), MealId: chicken.Id, Message: internal.ErrDuplicatedDate.Error()},
+		{Row: 7, Date: date(-1), MealId: pasta.Id, Message: internal.ErrDateNotFound.Error()},
+		{Row: 8, Date: "2040/01/01", MealId: pasta.Id, Message: internal.ErrDateNotFound.Error()},
+	}, report.Errors)
+	s.Len(report.Calendar, 28, "the days out of the calendar are not written")
+	s.Equal(pasta.Id, report.Calendar[0].MealId)
+	s.Equal(pasta.Name, report.Calendar[0].Name)
+	s.Equal(3, report.Calendar[0].Servings, "the servings of the day are kept")
+	s.Equal(chicken.Id, report.Calendar[2].MealId, "invalid rows are not written")
+	s.Equal(chicken.Id, report.Calendar[4].MealId, "rows without a meal do not erase the day")
+
+	c, rec = importCSV(bytes.NewBufferString("name,kcal\npizza,800\n"), "text/csv")
+	s.Error(api.ImportCalendarCSVHandler(c))
+	s.Equal(http.StatusBadRequest, rec.Code)
+	errorReturned := new(internal.ErrorResponse)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), errorReturned))
+	s.True(strings.HasPrefix(errorReturned.Err.Message, "el fichero CSV"))
+}
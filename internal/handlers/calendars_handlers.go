@@ -11,8 +11,12 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"net/http"
+	"strconv"
+	"strings"
 )
 
+const mimeTextCSV = "text/csv; charset=UTF-8"
+
 type CalendarAPI struct {
 	DB      database.Database
 	Manager managers.ICalendarManager
@@ -30,13 +34,11 @@ func (a *CalendarAPI) PostCalendarHandler(c echo.Context) error {
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
-	finalCal, err := a.Manager.GetFrontCalendar(calendar)
-	if err != nil {
-		return internal.NewErrorResponse(c, err)
-	}
-	return c.JSON(http.StatusCreated, finalCal)
+	return a.calendarResponse(c, http.StatusCreated, userID, calendar)
 }
 
+// GetCalendarHandler returns the calendar as JSON, or as CSV when the Accept
+// header asks for text/csv.
 func (a *CalendarAPI) GetCalendarHandler(c echo.Context) error {
 	var userID string
 	if err := url.ParseURLPath(c, url.PathMap{
@@ -44,15 +46,18 @@ func (a *CalendarAPI) GetCalendarHandler(c echo.Context) error {
 	}); err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
-	calendar, err := a.Manager.GetCalendar(userID)
-	if err != nil {
-		return internal.NewErrorResponse(c, err)
+	if strings.Contains(c.Request().Header.Get(echo.HeaderAccept), "text/csv") {
+		data, err := a.Manager.GetCalendarCSV(userID)
+		if err != nil {
+			return internal.NewErrorResponse(c, err)
+		}
+		return c.Blob(http.StatusOK, mimeTextCSV, data)
 	}
-	finalCal, err := a.Manager.GetFrontCalendar(calendar)
+	calendar, err := a.Manager.GetCalendar(userID)
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
-	return c.JSON(http.StatusOK, finalCal)
+	return a.calendarResponse(c, http.StatusOK, userID, calendar)
 }
 
 func (a *CalendarAPI) PutCalendarHandler(c echo.Context) error {
@@ -72,11 +77,27 @@ func (a *CalendarAPI) PutCalendarHandler(c echo.Context) error {
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
-	finalCal, err := a.Manager.GetFrontCalendar(calendar)
+	return a.calendarResponse(c, http.StatusOK, userID, calendar)
+}
+
+func (a *CalendarAPI) PatchCalendarHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+
+	var daysReq []models.Calendar
+	if err := c.Bind(&daysReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+
+	calendar, err := a.Manager.PatchCalendar(userID, daysReq)
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
-	return c.JSON(http.StatusOK, finalCal)
+	return a.calendarResponse(c, http.StatusOK, userID, calendar)
 }
 
 func (a *CalendarAPI) DeleteCalendarHandler(c echo.Context) error {
@@ -103,11 +124,48 @@ func (a *CalendarAPI) RedoCalendarHandler(c echo.Context) error {
 		return internal.NewErrorResponse(c, err)
 	}
 
-	err := a.Manager.DeleteCalendar(userID)
+	calendar, err := a.Manager.RedoCalendar(userID)
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
-	calendar, err := a.Manager.CreateCalendar(userID)
+
+	return a.calendarResponse(c, http.StatusOK, userID, calendar)
+
+}
+
+func (a *CalendarAPI) RedoWeekCalendarHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	dates := &models.UpdateWeekCalendar{}
+	if err := c.Bind(dates); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+
+	calendar, err := a.Manager.UpdateDaysCalendar(userID, *dates)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+
+	return a.calendarResponse(c, http.StatusOK, userID, calendar)
+}
+
+func (a *CalendarAPI) CopyWeekCalendarHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	weeks := &models.CopyWeekCalendar{}
+	if err := c.Bind(weeks); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+
+	calendar, warnings, err := a.Manager.CopyWeekCalendar(userID, *weeks)
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
@@ -116,31 +174,79 @@ func (a *CalendarAPI) RedoCalendarHandler(c echo.Context) error {
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
-	return c.JSON(http.StatusOK, finalCal)
+	response := models.CopyWeekResponse{Calendar: finalCal, Warnings: warnings}
+	if wantsSummary(c) {
+		if response.Weeks, err = a.Manager.GetCalendarSummary(userID, calendar); err != nil {
+			return internal.NewErrorResponse(c, err)
+		}
+	}
 
+	return c.JSON(http.StatusOK, response)
 }
 
-func (a *CalendarAPI) RedoWeekCalendarHandler(c echo.Context) error {
+// ImportCalendarHandler imports the meals of an iCalendar file, sent as the
+// "file" field of a multipart form or as the body, merging them into the
+// calendar or replacing it as the mode query param says.
+func (a *CalendarAPI) ImportCalendarHandler(c echo.Context) error {
 	var userID string
 	if err := url.ParseURLPath(c, url.PathMap{
 		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
 	}); err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
-	dates := &models.UpdateWeekCalendar{}
-	if err := c.Bind(dates); err != nil {
-		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	ics, err := requestFile(c)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	defer ics.Close()
+	report, err := a.Manager.ImportCalendar(userID, ics, c.QueryParam(internal.QueryImportMode))
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
 	}
+	return c.JSON(http.StatusOK, report)
+}
 
-	calendar, err := a.Manager.UpdateDaysCalendar(userID, *dates)
+// ImportCalendarCSVHandler writes the valid rows of a CSV file, sent as the
+// "file" field of a multipart form or as the body, into the calendar and
+// reports the invalid ones.
+func (a *CalendarAPI) ImportCalendarCSVHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	data, err := requestFile(c)
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
+	defer data.Close()
+	report, err := a.Manager.ImportCalendarCSV(userID, data)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
 
+// calendarResponse writes the calendar as returned by every calendar endpoint,
+// wrapped with its weekly summaries when the summary query param is true.
+func (a *CalendarAPI) calendarResponse(c echo.Context, status int, userID string, calendar []models.Calendar) error {
 	finalCal, err := a.Manager.GetFrontCalendar(calendar)
 	if err != nil {
 		return internal.NewErrorResponse(c, err)
 	}
+	if !wantsSummary(c) {
+		return c.JSON(status, finalCal)
+	}
+	weeks, err := a.Manager.GetCalendarSummary(userID, calendar)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(status, models.CalendarSummaryResponse{Calendar: finalCal, Weeks: weeks})
+}
 
-	return c.JSON(http.StatusOK, finalCal)
+// wantsSummary tells whether the weekly summaries are asked for.
+func wantsSummary(c echo.Context) bool {
+	summary, err := strconv.ParseBool(c.QueryParam(internal.QuerySummary))
+	return err == nil && summary
 }
@@ -5,11 +5,13 @@ import (
 	"calendar/internal"
 	"calendar/internal/managers"
 	"calendar/internal/models"
+	"calendar/internal/repositories"
 	"calendar/pkg/database"
 	"fmt"
 	"github.com/json-iterator/go"
 	"github.com/labstack/echo/v4"
 	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"net/http/httptest"
@@ -132,6 +134,44 @@ func (s *CalendarAPITestSuite) TearDownTest() {
 	_ = database.RemoveDB(databaseTest)
 }
 
+// currentMonday returns the monday of the current week, the first day of the
+// calendar window.
+func currentMonday() time.Time {
+	today := time.Now()
+	return today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+}
+
+// windowCalendar returns the 28 days of the current calendar window of the
+// user, the given days in place of the ones with their date and the rest
+// without a meal, so the calendar is not moved when read.
+func windowCalendar(userID string, days ...models.Calendar) (calendar []models.Calendar) {
+	byDate := make(map[string]models.Calendar, len(days))
+	for _, day := range days {
+		byDate[day.Date] = day
+	}
+	monday := currentMonday()
+	for i := 0; i < 28; i++ {
+		date := monday.AddDate(0, 0, i).Format("2006/01/02")
+		day, ok := byDate[date]
+		if !ok {
+			day = models.Calendar{UserId: userID, Name: models.NoMeal, Date: date}
+		}
+		calendar = append(calendar, day)
+	}
+	return
+}
+
+// requireWindow checks that the stored calendar of the user is made of the 28
+// days of the current calendar window.
+func (s *CalendarAPITestSuite) requireWindow(userID string) {
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	s.Len(calendar, 28)
+	for i, day := range calendar {
+		s.Equal(currentMonday().AddDate(0, 0, i).Format("2006/01/02"), day.Date)
+	}
+}
+
 func (s *CalendarAPITestSuite) TestPostCalendarHandler() {
 	tests := []struct {
 		name               string
@@ -176,7 +216,7 @@ func (s *CalendarAPITestSuite) TestPostCalendarHandler() {
 			calendarManager := managers.NewCalendarManager(*s.db)
 			api := CalendarAPI{DB: *s.db, Manager: calendarManager}
 
-			s.httpMock.On("GetAllMeals", t.userId).Return(mealsDb, nil).Once()
+			s.httpMock.On("GetAllMeals", t.userId, mock.Anything).Return(mealsDb, nil).Once()
 			for i, meal := range mealsDb {
 				s.httpMock.On("GetMeal", t.userId, meal.Id).Return(models.MealToFront{Name: fmt.Sprintf("meal%d", i)}, nil)
 			}
@@ -252,7 +292,7 @@ func (s *CalendarAPITestSuite) TestGetCalendarHandler() {
 			calendarManager := managers.NewCalendarManager(*s.db)
 			api := CalendarAPI{DB: *s.db, Manager: calendarManager}
 
-			s.httpMock.On("GetAllMeals", t.userID).Return(mealsDb, nil).Once()
+			s.httpMock.On("GetAllMeals", t.userID, mock.Anything).Return(mealsDb, nil).Once()
 			for i, meal := range mealsDb {
 				s.httpMock.On("GetMeal", t.userID, meal.Id).Return(models.MealToFront{Name: fmt.Sprintf("meal%d", i)}, nil)
 			}
@@ -325,7 +365,7 @@ func (s *CalendarAPITestSuite) TestPutCalendarHandler() {
 			calendarManager := managers.NewCalendarManager(*s.db)
 			api := CalendarAPI{DB: *s.db, Manager: calendarManager}
 
-			s.httpMock.On("GetAllMeals", t.userID).Return(mealsDb, nil).Once()
+			s.httpMock.On("GetAllMeals", t.userID, mock.Anything).Return(mealsDb, nil).Once()
 			for i, meal := range mealsDb {
 				s.httpMock.On("GetMeal", t.userID, meal.Id).Return(models.MealToFront{Name: fmt.Sprintf("meal%d", i)}, nil)
 			}
@@ -348,6 +388,108 @@ func (s *CalendarAPITestSuite) TestPutCalendarHandler() {
 	}
 }
 
+func (s *CalendarAPITestSuite) TestPatchCalendarHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:   "Patch calendar days (ok)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: []models.Calendar{
+				{MealId: "01FN3EEB2NVFJAHAPM00000010", Date: time.Now().Format("2006/01/02")},
+				{MealId: "01FN3EEB2NVFJAHAPM00000011", Date: time.Now().AddDate(0, 0, 1).Format("2006/01/02")},
+			},
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:   "Patch calendar days, invalid days (400)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: []models.Calendar{
+				{MealId: "01FN3EEB2NVFJAHAPM00000010", Date: time.Now().Format("2006/01/02")},
+				{MealId: "01FN3EEB2NVFJAHAPM00000011", Date: time.Now().Format("02-01-2006")},
+				{MealId: "01FN3EEB2NVFJAHAPM00000012", Date: time.Now().AddDate(0, 0, 5).Format("2006/01/02")},
+			},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrInvalidCalendarDays.Error(),
+					Details: []internal.ItemError{
+						{
+							Index:   1,
+							Date:    time.Now().Format("02-01-2006"),
+							MealId:  "01FN3EEB2NVFJAHAPM00000011",
+							Message: internal.ErrInvalidDateFormat.Error(),
+						},
+						{
+							Index:   2,
+							Date:    time.Now().AddDate(0, 0, 5).Format("2006/01/02"),
+							MealId:  "01FN3EEB2NVFJAHAPM00000012",
+							Message: internal.ErrDateNotFound.Error(),
+						},
+					},
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name: "Patch calendar days, userId not indicated (400)",
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrUserIDNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, internal.RouteCalendar, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			calendarManager := managers.NewCalendarManager(*s.db)
+			api := CalendarAPI{DB: *s.db, Manager: calendarManager}
+
+			for i, meal := range mealsDb {
+				s.httpMock.On("GetMeal", t.userID, meal.Id).Return(models.MealToFront{Name: fmt.Sprintf("meal%d", i)}, nil)
+			}
+
+			c := getEchoContext(t.userID, t.reqBody)
+			err := api.PatchCalendarHandler(c)
+
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+				s.True(ok)
+				body := resp.Body.Bytes()
+
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
+
 func (s *CalendarAPITestSuite) TestDeleteCalendarHandler() {
 	tests := []struct {
 		name               string
@@ -401,7 +543,7 @@ func (s *CalendarAPITestSuite) TestDeleteCalendarHandler() {
 		s.Run(t.name, func() {
 			calendarManager := managers.NewCalendarManager(*s.db)
 			api := CalendarAPI{DB: *s.db, Manager: calendarManager}
-			s.httpMock.On("GetAllMeals", t.userID).Return(mealsDb, nil).Once()
+			s.httpMock.On("GetAllMeals", t.userID, mock.Anything).Return(mealsDb, nil).Once()
 			for i, meal := range mealsDb {
 				s.httpMock.On("GetMeal", t.userID, meal.Id).Return(models.MealToFront{Name: fmt.Sprintf("meal%d", i)}, nil)
 			}
@@ -427,6 +569,7 @@ func (s *CalendarAPITestSuite) TestRedoCalendarHandler() {
 	tests := []struct {
 		name               string
 		userID             string
+		meals              []*models.MealToFront
 		expectedResp       interface{}
 		expectedStatusCode int
 		wantErr            bool
@@ -434,9 +577,23 @@ func (s *CalendarAPITestSuite) TestRedoCalendarHandler() {
 		{
 			name:               "Redo calendar (ok)",
 			userID:             "01FN3EEB2NVFJAHAPU00000002",
+			meals:              mealsDb,
 			expectedStatusCode: http.StatusOK,
 			wantErr:            false,
 		},
+		{
+			name:   "Redo calendar, no meals keeps the calendar (404)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			meals:  []*models.MealToFront{},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusNotFound,
+					Message: internal.ErrMealsNotFound.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			wantErr:            true,
+		},
 		{
 			name: "Redo calendar, userId not indicated (400)",
 			expectedResp: &internal.ErrorResponse{
@@ -464,7 +621,7 @@ func (s *CalendarAPITestSuite) TestRedoCalendarHandler() {
 			calendarManager := managers.NewCalendarManager(*s.db)
 			api := CalendarAPI{DB: *s.db, Manager: calendarManager}
 
-			s.httpMock.On("GetAllMeals", t.userID).Return(mealsDb, nil).Once()
+			s.httpMock.On("GetAllMeals", t.userID, mock.Anything).Return(t.meals, nil).Once()
 			for i, meal := range mealsDb {
 				s.httpMock.On("GetMeal", t.userID, meal.Id).Return(models.MealToFront{Name: fmt.Sprintf("meal%d", i)}, nil)
 			}
@@ -482,6 +639,10 @@ func (s *CalendarAPITestSuite) TestRedoCalendarHandler() {
 				s.NoError(jsoniter.Unmarshal(body, errorReturned))
 				s.Equal(errorReturned, t.expectedResp)
 			}
+			if t.userID != "" {
+				_, errCal := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(t.userID)
+				s.NoError(errCal)
+			}
 			s.Equal(t.expectedStatusCode, c.Response().Status)
 		})
 	}
@@ -536,7 +697,7 @@ func (s *CalendarAPITestSuite) TestRedoWeekCalendarHandler() {
 			calendarManager := managers.NewCalendarManager(*s.db)
 			api := CalendarAPI{DB: *s.db, Manager: calendarManager}
 
-			s.httpMock.On("GetAllMeals", t.userID).Return(mealsDb, nil).Once()
+			s.httpMock.On("GetAllMeals", t.userID, mock.Anything).Return(mealsDb, nil).Once()
 			for i, meal := range mealsDb {
 				s.httpMock.On("GetMeal", t.userID, meal.Id).Return(models.MealToFront{Name: fmt.Sprintf("meal%d", i)}, nil)
 			}
@@ -558,3 +719,143 @@ func (s *CalendarAPITestSuite) TestRedoWeekCalendarHandler() {
 		})
 	}
 }
+
+func (s *CalendarAPITestSuite) TestCopyWeekCalendarHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		summary            bool
+		expectedWarnings   int
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:   "Copy calendar week (ok)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.CopyWeekCalendar{
+				From:         time.Now().Format("2006/01/02"),
+				To:           time.Now().AddDate(0, 0, 1).Format("2006/01/02"),
+				CheckRepeats: true,
+			},
+			expectedWarnings:   1,
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:   "Copy calendar week with its summary (ok)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.CopyWeekCalendar{
+				From: time.Now().Format("2006/01/02"),
+				To:   time.Now().AddDate(0, 0, 1).Format("2006/01/02"),
+			},
+			summary:            true,
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:   "Copy calendar week, date not in calendar (404)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.CopyWeekCalendar{
+				From: time.Now().Format("2006/01/02"),
+				To:   time.Now().AddDate(0, 0, 7).Format("2006/01/02"),
+			},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusNotFound,
+					Message: internal.ErrDateNotFound.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			wantErr:            true,
+		},
+		{
+			name: "Copy calendar week, userId not indicated (400)",
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrUserIDNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}, summary bool) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("%s?summary=%t", internal.RouteCalendarCopy, summary), bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			calendarManager := managers.NewCalendarManager(*s.db)
+			api := CalendarAPI{DB: *s.db, Manager: calendarManager}
+
+			c := getEchoContext(t.userID, t.reqBody, t.summary)
+			err := api.CopyWeekCalendarHandler(c)
+
+			resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+			s.True(ok)
+			body := resp.Body.Bytes()
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			} else {
+				copyResp := new(models.CopyWeekResponse)
+				s.NoError(jsoniter.Unmarshal(body, copyResp))
+				s.Len(copyResp.Warnings, t.expectedWarnings)
+				s.Equal(t.summary, len(copyResp.Weeks) > 0, "the weeks are only returned when asked for")
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
+
+func (s *CalendarAPITestSuite) TestPutCalendarHandlerServings() {
+	userID := "01FN3EEB2NVFJAHAPU00000014"
+	mealID := "01FN3EEB2NVFJAHAPM00000701"
+	date := time.Now().Format("2006/01/02")
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar([]models.Calendar{
+		{UserId: userID, MealId: mealID, Name: "meal", Date: date, Servings: 2},
+	}))
+	s.httpMock.On("GetMeal", userID, mealID).Return(models.MealToFront{Name: "meal"}, nil)
+
+	put := func(body string) (echo.Context, error) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, internal.RouteCalendar, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+		return c, api.PutCalendarHandler(c)
+	}
+	servingsOf := func(c echo.Context) int {
+		var response []models.Calendar
+		s.NoError(jsoniter.Unmarshal(c.Response().Writer.(*httptest.ResponseRecorder).Body.Bytes(), &response))
+		return response[len(response)-1].Servings
+	}
+
+	c, err := put(fmt.Sprintf(`{"meal_id":"%s","date":"%s","servings":6}`, mealID, date))
+	s.NoError(err)
+	s.Equal(6, servingsOf(c))
+
+	c, err = put(fmt.Sprintf(`{"meal_id":"%s","date":"%s"}`, mealID, date))
+	s.NoError(err)
+	s.Equal(6, servingsOf(c), "servings are kept when not given")
+
+	c, err = put(fmt.Sprintf(`{"meal_id":"%s","date":"%s","servings":-1}`, mealID, date))
+	s.Error(err)
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/internal/utils"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestPutMealCostHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		mealID             string
+		reqBody            interface{}
+		mealErr            error
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:               "Update meal cost (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000018",
+			mealID:             "01FN3EEB2NVFJAHAPM00000001",
+			reqBody:            models.MealCost{Cost: 12.5},
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:    "Update meal cost, negative cost (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000018",
+			mealID:  "01FN3EEB2NVFJAHAPM00000001",
+			reqBody: models.MealCost{Cost: -1},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Update meal cost, meal not found (404)",
+			userID:  "01FN3EEB2NVFJAHAPU00000018",
+			mealID:  "01FN3EEB2NVFJAHAPM00000002",
+			reqBody: models.MealCost{Cost: 3},
+			mealErr: internal.ErrMealNotFound,
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusNotFound,
+					Message: internal.ErrMealNotFound.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId, mealId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, internal.RouteMealCost, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID, internal.ParamMealID)
+		c.SetParamValues(userId, mealId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			s.httpMock.On("GetMeal", t.userID, t.mealID).Return(models.MealToFront{Name: "meal"}, t.mealErr).Once()
+			api := MealCostAPI{DB: *s.db, Manager: managers.NewMealCostManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.mealID, t.reqBody)
+			err := api.PutMealCostHandler(c)
+
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+				s.True(ok)
+				body := resp.Body.Bytes()
+
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+
+	costs, err := managers.NewMealCostManager(*s.db).GetMealCosts("01FN3EEB2NVFJAHAPU00000018")
+	s.NoError(err)
+	s.Len(costs, 1)
+	s.Equal(12.5, costs[0].Cost)
+}
+
+func (s *CalendarAPITestSuite) TestIngredientPriceHandlers() {
+	userID := "01FN3EEB2NVFJAHAPU00000018"
+	api := IngredientPriceAPI{DB: *s.db, Manager: managers.NewIngredientPriceManager(*s.db)}
+	getEchoContext := func(method, priceId string, request interface{}) (echo.Context, *httptest.ResponseRecorder) {
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(method, internal.RouteIngredientPrice, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID, internal.ParamPriceID)
+		c.SetParamValues(userID, priceId)
+		return c, rec
+	}
+
+	c, rec := getEchoContext(http.MethodPost, "", models.IngredientPrice{Ingredient: " arroz ", Price: 2, Quantity: 1, Unit: "kg"})
+	s.NoError(api.PostIngredientPriceHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+	created := new(models.IngredientPrice)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), created))
+	s.Equal("arroz", created.Ingredient)
+	s.NotEmpty(created.Id)
+
+	c, _ = getEchoContext(http.MethodPost, "", models.IngredientPrice{Ingredient: "arroz", Price: -2})
+	s.Error(api.PostIngredientPriceHandler(c))
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+
+	c, rec = getEchoContext(http.MethodPut, created.Id, models.IngredientPrice{Ingredient: "arroz", Price: 2.4, Quantity: 1, Unit: "kg"})
+	s.NoError(api.PutIngredientPriceHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+	updated := new(models.IngredientPrice)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), updated))
+	s.Equal(2.4, updated.Price)
+
+	c, _ = getEchoContext(http.MethodDelete, created.Id, nil)
+	s.NoError(api.DeleteIngredientPriceHandler(c))
+	s.Equal(http.StatusNoContent, c.Response().Status)
+
+	c, _ = getEchoContext(http.MethodDelete, created.Id, nil)
+	s.Error(api.DeleteIngredientPriceHandler(c))
+	s.Equal(http.StatusNotFound, c.Response().Status)
+}
+
+func (s *CalendarAPITestSuite) TestGetCalendarSummaryWithCosts() {
+	userID := "01FN3EEB2NVFJAHAPU00000018"
+	stew := models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00001001", Name: "stew"}
+	rice := models.MealToFront{Name: "rice", Ingredients: []string{"200 g de arroz", "2 huevos", "perejil"}}
+	riceID := "01FN3EEB2NVFJAHAPM00001002"
+	s.NoError(repositories.NewSQLiteMealCostRepository(s.db).SaveMealCost(models.MealCost{UserId: userID, MealId: stew.Id, Cost: 10}))
+	prices := repositories.NewSQLiteIngredientPriceRepository(s.db)
+	s.NoError(prices.CreateIngredientPrice(models.IngredientPrice{Id: "01FN3EEB2NVFJAHAPP00000001", UserId: userID, Ingredient: "arroz", Price: 2, Quantity: 1, Unit: "kg"}))
+	s.NoError(prices.CreateIngredientPrice(models.IngredientPrice{Id: "01FN3EEB2NVFJAHAPP00000002", UserId: userID, Ingredient: "huevo", Price: 0.25}))
+	s.NoError(repositories.NewSQLiteSettingsRepository(s.db).SaveSettings(models.UserSettings{UserId: userID, WeeklyBudget: 28}))
+	s.httpMock.On("GetMeal", userID, riceID).Return(rice, nil).Once()
+
+	monday := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	mealIds := []string{stew.Id, stew.Id, riceID, riceID, stew.Id, riceID, stew.Id}
+	var calendar []models.Calendar
+	for i, mealId := range mealIds {
+		calendar = append(calendar, models.Calendar{UserId: userID, MealId: mealId, Date: monday.AddDate(0, 0, i).Format("2006/01/02")})
+	}
+	calendar[1].LeftoverOf = calendar[0].Date
+
+	weeks, err := managers.NewCalendarManager(*s.db).GetCalendarSummary(userID, calendar)
+	s.NoError(err)
+	s.Len(weeks, 1)
+	s.Equal(32.7, weeks[0].Cost, "3 stews of 10 and 3 rices of 0.4 + 0.5, leftovers are free")
+	s.Equal(28.0, weeks[0].Budget)
+	s.Equal(4.7, weeks[0].BudgetDeviation)
+}
+
+func (s *CalendarAPITestSuite) TestBudgetScore() {
+	settings := models.NewUserSettings("01FN3EEB2NVFJAHAPU00000018")
+	settings.WeeklyBudget = 64
+	tools := utils.NewCalendarToolsManager().WithPreferences(utils.Preferences{
+		Settings: settings,
+		Costs:    map[string]float64{"01FN3EEB2NVFJAHAPM00001101": 12},
+		Prices:   []models.IngredientPrice{{Ingredient: "salmón", Price: 20, Quantity: 1, Unit: "kg"}},
+	})
+	calendar := []models.Calendar{
+		{MealId: "01FN3EEB2NVFJAHAPM00001101", Date: "2030/01/07"},
+		{MealId: "01FN3EEB2NVFJAHAPM00001101", Date: "2030/01/08"},
+	}
+	cheap := &models.MealToFront{Ingredients: []string{"250 g de salmon"}}
+	pricey := &models.MealToFront{Ingredients: []string{"600 g de salmón"}}
+	wednesday := time.Date(2030, 1, 9, 0, 0, 0, 0, time.UTC)
+
+	cost, ok := tools.MealCost(pricey)
+	s.True(ok)
+	s.InDelta(12.0, cost, 1e-9)
+	s.InDelta(0, tools.BudgetScore(calendar, cheap, wednesday, nil), 1e-9, "40 left, 8 for each of the 5 days left")
+	s.InDelta(-1.0, tools.BudgetScore(calendar, pricey, wednesday, nil), 1e-9, "half more than the share of the day")
+	s.InDelta(0, tools.BudgetScore(calendar, pricey, wednesday.AddDate(0, 0, 7), nil), 1e-9, "next week")
+
+	for _, date := range []string{"2030/01/10", "2030/01/11", "2030/01/12"} {
+		calendar = append(calendar, models.Calendar{MealId: "01FN3EEB2NVFJAHAPM00001101", Date: date})
+	}
+	s.InDelta(-4.0, tools.BudgetScore(calendar, cheap, wednesday, nil), 1e-9, "more than the 4 left")
+
+	_, ok = tools.MealCost(&models.MealToFront{Ingredients: []string{"pollo"}})
+	s.False(ok)
+}
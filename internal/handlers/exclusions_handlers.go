@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type ExclusionAPI struct {
+	DB      database.Database
+	Manager managers.IExclusionManager
+}
+
+func (a *ExclusionAPI) GetExclusionsHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	exclusions, err := a.Manager.GetExclusions(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, exclusions)
+}
+
+func (a *ExclusionAPI) PostExclusionHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	exclusionReq := &models.IngredientExclusion{}
+	if err := c.Bind(exclusionReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	exclusion, err := a.Manager.CreateExclusion(userID, *exclusionReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, exclusion)
+}
+
+func (a *ExclusionAPI) PutExclusionHandler(c echo.Context) error {
+	var userID, exclusionID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:      {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamExclusionID: {Target: &exclusionID, Err: internal.ErrExclusionIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	exclusionReq := &models.IngredientExclusion{}
+	if err := c.Bind(exclusionReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	exclusion, err := a.Manager.UpdateExclusion(userID, exclusionID, *exclusionReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, exclusion)
+}
+
+func (a *ExclusionAPI) DeleteExclusionHandler(c echo.Context) error {
+	var userID, exclusionID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:      {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamExclusionID: {Target: &exclusionID, Err: internal.ErrExclusionIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteExclusion(userID, exclusionID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"fmt"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestPostExclusionHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:               "Create permanent exclusion (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000002",
+			reqBody:            models.IngredientExclusion{Ingredient: "cacahuete", Reason: "alergia"},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:               "Create temporary exclusion (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000002",
+			reqBody:            models.IngredientExclusion{Ingredient: "marisco", From: "2022/05/01", Until: "2022/05/07"},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:    "Create exclusion, ingredient not indicated (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.IngredientExclusion{Ingredient: " "},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Create exclusion, until before from (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.IngredientExclusion{Ingredient: "marisco", From: "2022/05/07", Until: "2022/05/01"},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Create exclusion, wrong date format (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.IngredientExclusion{Ingredient: "marisco", From: "01-05-2022"},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrInvalidDateFormat.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RouteExclusions, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := ExclusionAPI{DB: *s.db, Manager: managers.NewExclusionManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.reqBody)
+			err := api.PostExclusionHandler(c)
+
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+				s.True(ok)
+				body := resp.Body.Bytes()
+
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
+
+func (s *CalendarAPITestSuite) TestPutCalendarHandlerExcludedMeal() {
+	userID := "01FN3EEB2NVFJAHAPU00000002"
+	mealID := "01FN3EEB2NVFJAHAPM00000200"
+	today := time.Now().Format("2006/01/02")
+	s.NoError(repositories.NewSQLiteExclusionRepository(s.db).CreateExclusion(models.IngredientExclusion{
+		Id:         "01FN3EEB2NVFJAHAPX00000001",
+		UserId:     userID,
+		Ingredient: "Nueces",
+	}))
+	s.httpMock.On("GetMeal", userID, mealID).Return(models.MealToFront{Name: "brownie", Ingredients: []string{"chocolate", "nueces pecanas"}}, nil)
+
+	body, err := jsoniter.Marshal(models.Calendar{MealId: mealID, Date: today})
+	s.NoError(err)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, internal.RouteCalendar, bytes.NewBuffer(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.Error(api.PutCalendarHandler(c))
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+	errorReturned := new(internal.ErrorResponse)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), errorReturned))
+	s.Equal(internal.ErrExcludedIngredient.Error(), errorReturned.Err.Message)
+
+	day, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendarSpecificDate(userID, today)
+	s.NoError(err)
+	s.Equal("01FN3EEB2NVFJAHAPM00000001", day[0].MealId)
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerWithExclusions() {
+	userID := "01FN3EEB2NVFJAHAPU00000005"
+	ingredients := []string{"nueces", "pollo", "arroz"}
+	var meals []*models.MealToFront
+	for i := 0; i < 9; i++ {
+		meals = append(meals, &models.MealToFront{
+			Id:          fmt.Sprintf("01FN3EEB2NVFJAHAPM0000020%d", i),
+			UserId:      userID,
+			Name:        fmt.Sprintf("meal%d", i),
+			Type:        models.Normal,
+			Ingredients: []string{ingredients[i%3]},
+		})
+	}
+	from := time.Now().AddDate(0, 0, 3).Format("2006/01/02")
+	until := time.Now().AddDate(0, 0, 5).Format("2006/01/02")
+	exclusionsDb := repositories.NewSQLiteExclusionRepository(s.db)
+	s.NoError(exclusionsDb.CreateExclusion(models.IngredientExclusion{Id: "01FN3EEB2NVFJAHAPX00000001", UserId: userID, Ingredient: "nueces"}))
+	s.NoError(exclusionsDb.CreateExclusion(models.IngredientExclusion{Id: "01FN3EEB2NVFJAHAPX00000002", UserId: userID, Ingredient: "pollo", From: from, Until: until}))
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PostCalendarHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	ingredientOf := func(mealId string) string {
+		for _, m := range meals {
+			if m.Id == mealId {
+				return m.Ingredients[0]
+			}
+		}
+		return ""
+	}
+	for _, day := range calendar {
+		s.NotEqual("nueces", ingredientOf(day.MealId))
+		if day.Date >= from && day.Date <= until {
+			s.Equal("arroz", ingredientOf(day.MealId))
+		}
+	}
+}
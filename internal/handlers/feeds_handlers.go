@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+	"strings"
+	"time"
+)
+
+type FeedAPI struct {
+	DB      database.Database
+	Manager managers.IFeedManager
+}
+
+func (a *FeedAPI) PostFeedTokenHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	token, err := a.Manager.CreateFeedToken(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, token)
+}
+
+func (a *FeedAPI) PutFeedTokenHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	token, err := a.Manager.RotateFeedToken(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, token)
+}
+
+func (a *FeedAPI) DeleteFeedTokenHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.RevokeFeedToken(userID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetFeedHandler serves the feed of the token, with the same options as the
+// iCalendar export of the calendar. Requests whose If-None-Match or
+// If-Modified-Since match the current version of the feed get a 304.
+func (a *FeedAPI) GetFeedHandler(c echo.Context) error {
+	var token string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamFeedToken: {Target: &token, Err: internal.ErrFeedTokenNotFound},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if !strings.HasSuffix(token, ".ics") {
+		return internal.NewErrorResponse(c, internal.ErrFeedTokenNotFound)
+	}
+	options, err := icsOptions(c)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	feed, err := a.Manager.GetFeed(strings.TrimSuffix(token, ".ics"), options)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	etag := `"` + feed.ETag + `"`
+	c.Response().Header().Set(echo.HeaderLastModified, feed.Modified.UTC().Format(http.TimeFormat))
+	c.Response().Header().Set("ETag", etag)
+	if notModified(c.Request(), etag, feed.Modified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, mimeTextCalendar, feed.Data)
+}
+
+// notModified tells whether the conditional headers of the request match the
+// version of the feed. If-Modified-Since is ignored when If-None-Match is given.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, m := range strings.Split(match, ",") {
+			m = strings.TrimPrefix(strings.TrimSpace(m), "W/")
+			if m == etag || m == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get(echo.HeaderIfModifiedSince))
+	return err == nil && !modified.Truncate(time.Second).After(since)
+}
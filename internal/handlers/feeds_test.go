@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (s *CalendarAPITestSuite) TestFeedHandlers() {
+	userID := "01FN3EEB2NVFJAHAPU00000020"
+	tuesday := currentMonday().AddDate(0, 0, 1).Format("2006/01/02")
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(windowCalendar(userID,
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00001301", Name: "paella", Date: currentMonday().Format("2006/01/02")},
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00001302", Name: "gazpacho", Date: tuesday},
+	)))
+	api := FeedAPI{DB: *s.db, Manager: managers.NewFeedManager(*s.db)}
+	tokenRequest := func(method string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(method, internal.RouteFeedToken, nil), rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		return c, rec
+	}
+	feed := func(token string, headers map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/feeds/"+token, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamFeedToken)
+		c.SetParamValues(token)
+		return c, rec
+	}
+
+	c, rec := tokenRequest(http.MethodPost)
+	s.NoError(api.PostFeedTokenHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+	token := new(models.FeedToken)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), token))
+	s.NotEmpty(token.Token)
+	s.Equal("/feeds/"+token.Token+".ics", token.URL)
+	stored, err := repositories.NewSQLiteFeedRepository(s.db).GetFeedToken(userID)
+	s.NoError(err)
+	s.NotContains(stored.Hash, token.Token, "only the hash is stored")
+
+	c, _ = tokenRequest(http.MethodPost)
+	s.Error(api.PostFeedTokenHandler(c))
+	s.Equal(http.StatusConflict, c.Response().Status)
+
+	c, rec = feed(token.Token+".ics", nil)
+	s.NoError(api.GetFeedHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+	s.Contains(rec.Body.String(), "SUMMARY:paella\r\n")
+	etag := rec.Header().Get("ETag")
+	modified := rec.Header().Get(echo.HeaderLastModified)
+	s.NotEmpty(etag)
+	s.NotEmpty(modified)
+	body := rec.Body.String()
+
+	c, rec = feed(token.Token+".ics", nil)
+	s.NoError(api.GetFeedHandler(c))
+	s.Equal(etag, rec.Header().Get("ETag"))
+	s.Equal(body, rec.Body.String(), "unchanged plans are served the same")
+
+	c, _ = feed(token.Token+".ics", map[string]string{"If-None-Match": etag})
+	s.NoError(api.GetFeedHandler(c))
+	s.Equal(http.StatusNotModified, c.Response().Status)
+	c, _ = feed(token.Token+".ics", map[string]string{echo.HeaderIfModifiedSince: modified})
+	s.NoError(api.GetFeedHandler(c))
+	s.Equal(http.StatusNotModified, c.Response().Status)
+
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).UpdateCalendar(userID, models.Calendar{MealId: "01FN3EEB2NVFJAHAPM00001303", Name: "tortilla", Date: tuesday}))
+	c, rec = feed(token.Token+".ics", map[string]string{"If-None-Match": etag})
+	s.NoError(api.GetFeedHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+	s.NotEqual(etag, rec.Header().Get("ETag"))
+	s.Contains(rec.Body.String(), "SUMMARY:tortilla\r\n")
+
+	c, _ = feed(token.Token, nil)
+	s.Error(api.GetFeedHandler(c))
+	s.Equal(http.StatusNotFound, c.Response().Status)
+
+	c, rec = tokenRequest(http.MethodPut)
+	s.NoError(api.PutFeedTokenHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+	rotated := new(models.FeedToken)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), rotated))
+	s.NotEqual(token.Token, rotated.Token)
+	c, _ = feed(token.Token+".ics", nil)
+	s.Error(api.GetFeedHandler(c))
+	s.Equal(http.StatusNotFound, c.Response().Status)
+	c, _ = feed(rotated.Token+".ics", nil)
+	s.NoError(api.GetFeedHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+
+	c, _ = tokenRequest(http.MethodDelete)
+	s.NoError(api.DeleteFeedTokenHandler(c))
+	s.Equal(http.StatusNoContent, c.Response().Status)
+	c, _ = feed(rotated.Token+".ics", nil)
+	s.Error(api.GetFeedHandler(c))
+	s.Equal(http.StatusNotFound, c.Response().Status)
+	c, _ = tokenRequest(http.MethodDelete)
+	s.Error(api.DeleteFeedTokenHandler(c))
+	s.Equal(http.StatusNotFound, c.Response().Status)
+}
+
+func (s *CalendarAPITestSuite) TestFeedHandlerVersionPerOptions() {
+	userID := "01FN3EEB2NVFJAHAPU00000028"
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(windowCalendar(userID,
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00002801", Name: "paella", Date: currentMonday().Format("2006/01/02")},
+	)))
+	api := FeedAPI{DB: *s.db, Manager: managers.NewFeedManager(*s.db)}
+	token, err := api.Manager.CreateFeedToken(userID)
+	s.NoError(err)
+	feed := func(query string) *httptest.ResponseRecorder {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, token.URL+query, nil), rec)
+		c.SetParamNames(internal.ParamFeedToken)
+		c.SetParamValues(token.Token + ".ics")
+		s.NoError(api.GetFeedHandler(c))
+		s.Equal(http.StatusOK, rec.Code)
+		return rec
+	}
+
+	allDay, atLunch := feed(""), feed("?time=14:00")
+	s.NotEqual(allDay.Header().Get("ETag"), atLunch.Header().Get("ETag"))
+	// The versions are dated back, so a version served again would move
+	// Last-Modified to now.
+	_, err = s.db.Conn.Exec("UPDATE feed_versions SET modified_at = ? WHERE user_id = ?", "2030-01-01T00:00:00Z", userID)
+	s.NoError(err)
+	for i := 0; i < 2; i++ {
+		for _, query := range []string{"", "?time=14:00", "?time=14:00&duration=60", "?ingredients=false"} {
+			rec := feed(query)
+			s.Equal("Tue, 01 Jan 2030 00:00:00 GMT", rec.Header().Get(echo.HeaderLastModified), "options %q", query)
+		}
+	}
+	var versions int
+	s.NoError(s.db.Conn.Get(&versions, "SELECT count(*) FROM feed_versions WHERE user_id = ?", userID))
+	s.Equal(2, versions, "equivalent options share their version")
+	s.Equal(allDay.Header().Get("ETag"), feed("").Header().Get("ETag"))
+}
+
+func (s *CalendarAPITestSuite) TestFeedHandlerMovesCalendar() {
+	userID := "01FN3EEB2NVFJAHAPU00000033"
+	lastMonday := currentMonday().AddDate(0, 0, -7)
+	var calendar []models.Calendar
+	for i := 0; i < 28; i++ {
+		calendar = append(calendar, models.Calendar{UserId: userID, MealId: mealsDb[i%len(mealsDb)].Id, Name: mealsDb[i%len(mealsDb)].Name, Date: lastMonday.AddDate(0, 0, i).Format("2006/01/02")})
+	}
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(calendar))
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(mealsDb, nil).Once()
+	api := FeedAPI{DB: *s.db, Manager: managers.NewFeedManager(*s.db)}
+	token, err := api.Manager.CreateFeedToken(userID)
+	s.NoError(err)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, token.URL, nil), rec)
+	c.SetParamNames(internal.ParamFeedToken)
+	c.SetParamValues(token.Token + ".ics")
+	s.NoError(api.GetFeedHandler(c))
+	s.Equal(http.StatusOK, rec.Code)
+	s.NotContains(rec.Body.String(), "DTSTART;VALUE=DATE:"+lastMonday.Format("20060102"), "the feed is not left behind")
+	s.Contains(rec.Body.String(), "DTSTART;VALUE=DATE:"+currentMonday().AddDate(0, 0, 27).Format("20060102"))
+	s.requireWindow(userID)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const mimeTextCalendar = "text/calendar; charset=UTF-8"
+
+type ICSAPI struct {
+	DB      database.Database
+	Manager managers.IICSManager
+}
+
+// GetCalendarICSHandler returns the calendar of the user as an iCalendar, with
+// the options given by the time, duration and ingredients query params.
+func (a *ICSAPI) GetCalendarICSHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	options, err := icsOptions(c)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	data, err := a.Manager.ExportCalendar(userID, options)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.Blob(http.StatusOK, mimeTextCalendar, data)
+}
+
+func icsOptions(c echo.Context) (options models.ICSOptions, err error) {
+	options.MealTime = c.QueryParam(internal.QueryMealTime)
+	if duration := c.QueryParam(internal.QueryDuration); duration != "" {
+		if options.Duration, err = strconv.Atoi(duration); err != nil {
+			return options, internal.ErrInvalidICSOptions
+		}
+	}
+	if ingredients := c.QueryParam(internal.QueryIngredients); ingredients != "" {
+		if options.Ingredients, err = strconv.ParseBool(ingredients); err != nil {
+			return options, internal.ErrInvalidICSOptions
+		}
+	}
+	return
+}
+
+// requestFile returns the file uploaded in the "file" field of a multipart form,
+// or the body of the request otherwise.
+func requestFile(c echo.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		return c.Request().Body, nil
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		return nil, internal.ErrWrongBody
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, internal.ErrWrongBody
+	}
+	return src, nil
+}
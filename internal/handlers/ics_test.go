@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/ical"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestGetCalendarICSHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000019"
+	lentils := models.MealToFront{Name: "Lentejas, con chorizo", Ingredients: []string{"300 g de lentejas", "1 chorizo", "zanahoria"}}
+	lentilsID := "01FN3EEB2NVFJAHAPM00001201"
+	monday := currentMonday()
+	date := func(days int, layout string) string { return monday.AddDate(0, 0, days).Format(layout) }
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(windowCalendar(userID,
+		models.Calendar{UserId: userID, MealId: lentilsID, Name: lentils.Name, Date: date(0, "2006/01/02"), Servings: 2},
+		models.Calendar{UserId: userID, MealId: lentilsID, Name: lentils.Name, Date: date(1, "2006/01/02"), Servings: 2, LeftoverOf: date(0, "2006/01/02")},
+	)))
+	s.httpMock.On("GetMeal", userID, lentilsID).Return(lentils, nil).Once()
+	api := ICSAPI{DB: *s.db, Manager: managers.NewICSManager(*s.db)}
+	get := func(query string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, internal.RouteCalendarICS+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		return c, rec
+	}
+
+	c, rec := get("")
+	s.NoError(api.GetCalendarICSHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+	s.Equal("text/calendar; charset=UTF-8", rec.Header().Get(echo.HeaderContentType))
+	body := rec.Body.String()
+	s.Contains(body, "SUMMARY:Lentejas\\, con chorizo\r\n")
+	s.Contains(body, "DTSTART;VALUE=DATE:"+date(0, "20060102")+"\r\n")
+	s.Contains(body, "DTEND;VALUE=DATE:"+date(1, "20060102")+"\r\n")
+	s.Contains(body, "UID:"+date(0, "20060102")+"-"+userID+"@calendars\r\n")
+	cal, err := ical.Parse(strings.NewReader(body))
+	s.NoError(err)
+	events := cal.Events()
+	s.Len(events, 2, "days without meal are not exported")
+	summary, _ := events[1].Get("SUMMARY")
+	s.Equal("Lentejas, con chorizo (sobras)", summary.Text())
+	description, _ := events[1].Get("DESCRIPTION")
+	s.Equal("Sobras del "+date(0, "2006/01/02")+"\nRaciones: 2", description.Text())
+
+	c, rec = get("?time=14:30&duration=45&ingredients=true")
+	s.NoError(api.GetCalendarICSHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+	cal, err = ical.Parse(strings.NewReader(rec.Body.String()))
+	s.NoError(err)
+	first := cal.Events()[0]
+	start, _ := first.Get("DTSTART")
+	s.Equal(date(0, "20060102")+"T143000", start.Value)
+	end, _ := first.Get("DTEND")
+	s.Equal(date(0, "20060102")+"T151500", end.Value)
+	description, _ = first.Get("DESCRIPTION")
+	s.Equal("Raciones: 2\nIngredientes:\n- 300 g de lentejas\n- 1 chorizo\n- zanahoria", description.Text())
+	uid, _ := first.Get("UID")
+	s.Equal(date(0, "20060102")+"-"+userID+"@calendars", uid.Value, "the UID does not depend on the options")
+
+	c, rec = get("?time=2pm")
+	s.Error(api.GetCalendarICSHandler(c))
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+	errorReturned := new(internal.ErrorResponse)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), errorReturned))
+	s.Equal(internal.ErrInvalidICSOptions.Error(), errorReturned.Err.Message)
+}
+
+// importICS returns an iCalendar with events in the week of the monday.
+func importICS(monday time.Time) string {
+	date := func(days int) string { return monday.AddDate(0, 0, days).Format("20060102") }
+	return "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:" + date(0) + "\r\n" +
+		"SUMMARY:lentejas con chorizo\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:" + date(1) + "\r\n" +
+		"SUMMARY:Lentejas\\, con chorizo (sobras)\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:" + date(3) + "T140000\r\n" +
+		"SUMMARY:Macarrnes con tomate\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:" + date(3) + "\r\n" +
+		"SUMMARY:Lentejas con chorizo\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:" + date(4) + "\r\n" +
+		"SUMMARY:Sushi\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+}
+
+func (s *CalendarAPITestSuite) TestImportCalendarHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000021"
+	meals := []*models.MealToFront{
+		{Id: "01FN3EEB2NVFJAHAPM00002101", Name: "Lentejas, con chorizo", Kcal: 600},
+		{Id: "01FN3EEB2NVFJAHAPM00002102", Name: "Macarrones con tomate", Kcal: 500},
+		{Id: "01FN3EEB2NVFJAHAPM00002103", Name: "Pollo asado", Kcal: 700},
+	}
+	today := time.Now()
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	date := func(days int) string { return monday.AddDate(0, 0, days).Format("2006/01/02") }
+	calendar := []models.Calendar{
+		{UserId: userID, MealId: meals[2].Id, Name: meals[2].Name, Date: date(0), Servings: 4},
+		{UserId: userID, MealId: "", Name: models.NoMeal, Date: date(1), Servings: 2},
+		{UserId: userID, MealId: meals[2].Id, Name: meals[2].Name, Date: date(2), Servings: 2},
+	}
+	for i := 3; i < 28; i++ {
+		calendar = append(calendar, models.Calendar{UserId: userID, Name: models.NoMeal, Date: date(i), Servings: 2})
+	}
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(calendar))
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	post := func(query, body string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RouteCalendarImport+query, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, "text/calendar")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		return c, rec
+	}
+
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil).Once()
+	c, rec := post("", importICS(monday))
+	s.NoError(api.ImportCalendarHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+	var report models.CalendarImport
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), &report))
+	s.Equal(models.ImportMerge, report.Mode)
+	s.Len(report.Imported, 3)
+	s.False(report.Imported[0].Fuzzy)
+	s.Equal(meals[1].Id, report.Imported[2].MealId)
+	s.True(report.Imported[2].Fuzzy)
+	s.Len(report.Unmatched, 2)
+	s.Equal(models.UnmatchedEvent{Date: date(3), Summary: "Lentejas con chorizo", Reason: "fecha repetida"}, report.Unmatched[0])
+	s.Equal(date(4), report.Unmatched[1].Date)
+	s.Len(report.Calendar, 28)
+	s.Equal(meals[0].Id, report.Calendar[0].MealId)
+	s.Equal(4, report.Calendar[0].Servings, "merging keeps the servings of the day")
+	s.Equal(date(0), report.Calendar[1].LeftoverOf)
+	s.Equal(meals[2].Id, report.Calendar[2].MealId, "days out of the file are kept when merging")
+	s.Equal(600, report.Calendar[0].Kcal)
+
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil).Once()
+	c, rec = post("?mode=replace", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:"+monday.AddDate(0, 0, 6).Format("20060102")+"\r\nSUMMARY:Pollo Asado\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n")
+	s.NoError(api.ImportCalendarHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+	report = models.CalendarImport{}
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), &report))
+	s.Len(report.Calendar, 28, "replacing keeps the 28 days of the calendar")
+	s.Equal(meals[2].Id, report.Calendar[6].MealId)
+	s.Equal(2, report.Calendar[6].Servings)
+	s.Equal(models.Calendar{UserId: userID, Name: models.NoMeal, Date: date(0), Servings: 2}, report.Calendar[0])
+	s.requireEditableWindow(userID, monday)
+
+	c, rec = post("?mode=overwrite", importICS(monday))
+	s.Error(api.ImportCalendarHandler(c))
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+	errorReturned := new(internal.ErrorResponse)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), errorReturned))
+	s.Equal(internal.ErrInvalidImportMode.Error(), errorReturned.Err.Message)
+}
+
+func (s *CalendarAPITestSuite) TestImportCalendarHandlerOutOfCalendar() {
+	userID := "01FN3EEB2NVFJAHAPU00000029"
+	meals := []*models.MealToFront{{Id: "01FN3EEB2NVFJAHAPM00002901", Name: "Pollo asado", Type: models.Normal}}
+	today := time.Now()
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	event := func(date string) string {
+		return "BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:" + date + "\r\nSUMMARY:Pollo asado\r\nEND:VEVENT\r\n"
+	}
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil)
+
+	for _, mode := range []string{models.ImportReplace, models.ImportMerge} {
+		e := echo.New()
+		body := "BEGIN:VCALENDAR\r\n" + event("20400101") + event(monday.AddDate(0, 0, -1).Format("20060102")) +
+			event(monday.AddDate(0, 0, 28).Format("20060102")) + event(monday.AddDate(0, 0, 27).Format("20060102")) + "END:VCALENDAR\r\n"
+		req := httptest.NewRequest(http.MethodPost, internal.RouteCalendarImport+"?mode="+mode, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, "text/calendar")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		s.NoError(api.ImportCalendarHandler(c))
+		var report models.CalendarImport
+		s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), &report))
+		s.Len(report.Imported, 1, mode)
+		s.Equal(monday.AddDate(0, 0, 27).Format("2006/01/02"), report.Imported[0].Date)
+		s.Len(report.Unmatched, 3)
+		for _, unmatched := range report.Unmatched {
+			s.Equal("fecha fuera del calendario", unmatched.Reason)
+		}
+
+		rec = httptest.NewRecorder()
+		c = e.NewContext(httptest.NewRequest(http.MethodGet, internal.RouteCalendar, nil), rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		s.NoError(api.GetCalendarHandler(c), mode)
+		s.Equal(http.StatusOK, rec.Code)
+		var calendar []models.Calendar
+		s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), &calendar))
+		s.Len(calendar, 28)
+		s.Equal(monday.AddDate(0, 0, 27).Format("2006/01/02"), calendar[27].Date)
+		s.requireEditableWindow(userID, monday)
+	}
+}
+
+func (s *CalendarAPITestSuite) TestImportCalendarHandlerMergeWithoutCalendar() {
+	userID := "01FN3EEB2NVFJAHAPU00000031"
+	meals := []*models.MealToFront{{Id: "01FN3EEB2NVFJAHAPM00003101", Name: "Pollo asado", Type: models.Normal}}
+	today := time.Now()
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil).Once()
+
+	e := echo.New()
+	body := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:" + monday.Format("20060102") + "\r\nSUMMARY:Pollo asado\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendarImport, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, "text/calendar")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+	s.Error(api.ImportCalendarHandler(c))
+	s.Equal(http.StatusNotFound, rec.Code)
+	_, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.ErrorIs(err, internal.ErrCalendarNotFound, "merging writes no loose days")
+}
+
+// requireEditableWindow checks that the stored calendar of the user is made of
+// the 28 consecutive days starting on the monday, and that a day of it can be
+// changed.
+func (s *CalendarAPITestSuite) requireEditableWindow(userID string, monday time.Time) {
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	s.Len(calendar, 28)
+	for i, day := range calendar {
+		s.Equal(monday.AddDate(0, 0, i).Format("2006/01/02"), day.Date)
+	}
+
+	meal := models.MealToFront{Name: "Garbanzos"}
+	mealID := "01FN3EEB2NVFJAHAPM00003199"
+	s.httpMock.On("GetMeal", userID, mealID).Return(meal, nil).Once()
+	date := monday.AddDate(0, 0, 13).Format("2006/01/02")
+	body, err := jsoniter.Marshal([]models.Calendar{{MealId: mealID, Date: date}})
+	s.NoError(err)
+	req := httptest.NewRequest(http.MethodPatch, internal.RouteCalendar, strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PatchCalendarHandler(c))
+	s.Equal(http.StatusOK, rec.Code)
+	day, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendarSpecificDate(userID, date)
+	s.NoError(err)
+	s.Equal(meal.Name, day[0].Name)
+}
+
+func (s *CalendarAPITestSuite) TestGetCalendarICSHandlerMovesCalendar() {
+	userID := "01FN3EEB2NVFJAHAPU00000032"
+	lastMonday := currentMonday().AddDate(0, 0, -7)
+	var calendar []models.Calendar
+	for i := 0; i < 28; i++ {
+		calendar = append(calendar, models.Calendar{UserId: userID, MealId: mealsDb[i%len(mealsDb)].Id, Name: mealsDb[i%len(mealsDb)].Name, Date: lastMonday.AddDate(0, 0, i).Format("2006/01/02")})
+	}
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(calendar))
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(mealsDb, nil).Once()
+	api := ICSAPI{DB: *s.db, Manager: managers.NewICSManager(*s.db)}
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, internal.RouteCalendarICS, nil), rec)
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+	s.NoError(api.GetCalendarICSHandler(c))
+	s.Equal(http.StatusOK, rec.Code)
+	s.NotContains(rec.Body.String(), "DTSTART;VALUE=DATE:"+lastMonday.Format("20060102"), "the past week is not exported")
+	s.Contains(rec.Body.String(), "DTSTART;VALUE=DATE:"+currentMonday().AddDate(0, 0, 27).Format("20060102"))
+	s.requireWindow(userID)
+}
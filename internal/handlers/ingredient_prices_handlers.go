@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type IngredientPriceAPI struct {
+	DB      database.Database
+	Manager managers.IIngredientPriceManager
+}
+
+func (a *IngredientPriceAPI) GetIngredientPricesHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	prices, err := a.Manager.GetIngredientPrices(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, prices)
+}
+
+func (a *IngredientPriceAPI) PostIngredientPriceHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	priceReq := &models.IngredientPrice{}
+	if err := c.Bind(priceReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	price, err := a.Manager.CreateIngredientPrice(userID, *priceReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, price)
+}
+
+func (a *IngredientPriceAPI) PutIngredientPriceHandler(c echo.Context) error {
+	var userID, priceID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:  {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamPriceID: {Target: &priceID, Err: internal.ErrPriceIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	priceReq := &models.IngredientPrice{}
+	if err := c.Bind(priceReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	price, err := a.Manager.UpdateIngredientPrice(userID, priceID, *priceReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, price)
+}
+
+func (a *IngredientPriceAPI) DeleteIngredientPriceHandler(c echo.Context) error {
+	var userID, priceID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:  {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamPriceID: {Target: &priceID, Err: internal.ErrPriceIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteIngredientPrice(userID, priceID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
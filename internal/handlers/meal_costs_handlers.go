@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type MealCostAPI struct {
+	DB      database.Database
+	Manager managers.IMealCostManager
+}
+
+func (a *MealCostAPI) GetMealCostsHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	costs, err := a.Manager.GetMealCosts(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, costs)
+}
+
+func (a *MealCostAPI) PutMealCostHandler(c echo.Context) error {
+	var userID, mealID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamMealID: {Target: &mealID, Err: internal.ErrMealIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	costReq := &models.MealCost{}
+	if err := c.Bind(costReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	cost, err := a.Manager.UpdateMealCost(userID, mealID, *costReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, cost)
+}
+
+func (a *MealCostAPI) DeleteMealCostHandler(c echo.Context) error {
+	var userID, mealID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamMealID: {Target: &mealID, Err: internal.ErrMealIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteMealCost(userID, mealID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
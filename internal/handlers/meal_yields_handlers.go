@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type MealYieldAPI struct {
+	DB      database.Database
+	Manager managers.IMealYieldManager
+}
+
+func (a *MealYieldAPI) GetMealYieldsHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	yields, err := a.Manager.GetMealYields(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, yields)
+}
+
+func (a *MealYieldAPI) PutMealYieldHandler(c echo.Context) error {
+	var userID, mealID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamMealID: {Target: &mealID, Err: internal.ErrMealIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	yieldReq := &models.MealYield{}
+	if err := c.Bind(yieldReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	yield, err := a.Manager.UpdateMealYield(userID, mealID, *yieldReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, yield)
+}
+
+func (a *MealYieldAPI) DeleteMealYieldHandler(c echo.Context) error {
+	var userID, mealID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamMealID: {Target: &mealID, Err: internal.ErrMealIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteMealYield(userID, mealID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"fmt"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestPutMealYieldHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		mealID             string
+		reqBody            interface{}
+		mealErr            error
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:               "Update meal yield (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000013",
+			mealID:             "01FN3EEB2NVFJAHAPM00000001",
+			reqBody:            models.MealYield{Days: 2},
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:    "Update meal yield, one day (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000013",
+			mealID:  "01FN3EEB2NVFJAHAPM00000001",
+			reqBody: models.MealYield{Days: 1},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Update meal yield, meal not found (404)",
+			userID:  "01FN3EEB2NVFJAHAPU00000013",
+			mealID:  "01FN3EEB2NVFJAHAPM00000002",
+			reqBody: models.MealYield{Days: 3},
+			mealErr: internal.ErrMealNotFound,
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusNotFound,
+					Message: internal.ErrMealNotFound.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId, mealId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, internal.RouteMealYield, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID, internal.ParamMealID)
+		c.SetParamValues(userId, mealId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			s.httpMock.On("GetMeal", t.userID, t.mealID).Return(models.MealToFront{Name: "meal"}, t.mealErr).Once()
+			api := MealYieldAPI{DB: *s.db, Manager: managers.NewMealYieldManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.mealID, t.reqBody)
+			err := api.PutMealYieldHandler(c)
+
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+				s.True(ok)
+				body := resp.Body.Bytes()
+
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+
+	yields, err := managers.NewMealYieldManager(*s.db).GetMealYields("01FN3EEB2NVFJAHAPU00000013")
+	s.NoError(err)
+	s.Len(yields, 1)
+	s.Equal(2, yields[0].Days)
+	s.Equal(1, yields[0].Offset)
+}
+
+func (s *CalendarAPITestSuite) TestDeleteMealYieldHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000013"
+	mealID := "01FN3EEB2NVFJAHAPM00000001"
+	s.NoError(repositories.NewSQLiteMealYieldRepository(s.db).SaveMealYield(models.MealYield{UserId: userID, MealId: mealID, Days: 2, Offset: 1}))
+	api := MealYieldAPI{DB: *s.db, Manager: managers.NewMealYieldManager(*s.db)}
+	getEchoContext := func() echo.Context {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, internal.RouteMealYield, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetParamNames(internal.ParamUserID, internal.ParamMealID)
+		c.SetParamValues(userID, mealID)
+		return c
+	}
+	c := getEchoContext()
+	s.NoError(api.DeleteMealYieldHandler(c))
+	s.Equal(http.StatusNoContent, c.Response().Status)
+	c = getEchoContext()
+	s.Error(api.DeleteMealYieldHandler(c))
+	s.Equal(http.StatusNotFound, c.Response().Status)
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerWithMealYield() {
+	userID := "01FN3EEB2NVFJAHAPU00000013"
+	var meals []*models.MealToFront
+	for i := 0; i < 6; i++ {
+		meals = append(meals, &models.MealToFront{
+			Id:     fmt.Sprintf("01FN3EEB2NVFJAHAPM0000050%d", i),
+			UserId: userID,
+			Name:   fmt.Sprintf("meal%d", i),
+			Type:   models.Normal,
+		})
+	}
+	stew := meals[0].Id
+	s.NoError(repositories.NewSQLiteMealYieldRepository(s.db).SaveMealYield(models.MealYield{UserId: userID, MealId: stew, Days: 2, Offset: 1}))
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PostCalendarHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	var cooked int
+	for i, day := range calendar {
+		if day.MealId != stew || day.LeftoverOf != "" {
+			continue
+		}
+		cooked++
+		if i+1 < len(calendar) {
+			s.Equal(stew, calendar[i+1].MealId)
+			s.Equal(day.Date, calendar[i+1].LeftoverOf)
+		}
+	}
+	s.NotZero(cooked)
+	for _, day := range calendar {
+		if day.LeftoverOf != "" {
+			cookedDate, _ := time.Parse("2006/01/02", day.LeftoverOf)
+			s.Equal(cookedDate.AddDate(0, 0, 1).Format("2006/01/02"), day.Date)
+		}
+	}
+}
+
+func (s *CalendarAPITestSuite) TestPutCalendarHandlerWithLeftovers() {
+	userID := "01FN3EEB2NVFJAHAPU00000013"
+	var meals []*models.MealToFront
+	for i := 0; i < 4; i++ {
+		meals = append(meals, &models.MealToFront{
+			Id:     fmt.Sprintf("01FN3EEB2NVFJAHAPM0000060%d", i),
+			UserId: userID,
+			Name:   fmt.Sprintf("meal%d", i),
+			Type:   models.Normal,
+		})
+	}
+	stew := meals[0]
+	s.NoError(repositories.NewSQLiteMealYieldRepository(s.db).SaveMealYield(models.MealYield{UserId: userID, MealId: stew.Id, Days: 2, Offset: 1}))
+	start := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	var calendar []models.Calendar
+	for i := 0; i < 7; i++ {
+		meal := meals[1+i%3]
+		calendar = append(calendar, models.Calendar{UserId: userID, MealId: meal.Id, Name: meal.Name, Date: start.AddDate(0, 0, i).Format("2006/01/02")})
+	}
+	calendar[0] = models.Calendar{UserId: userID, MealId: stew.Id, Name: stew.Name, Date: calendar[0].Date}
+	calendar[1] = models.Calendar{UserId: userID, MealId: stew.Id, Name: stew.Name, Date: calendar[1].Date, LeftoverOf: calendar[0].Date}
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(calendar))
+	for _, m := range meals {
+		s.httpMock.On("GetMeal", userID, m.Id).Return(*m, nil)
+	}
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil)
+
+	put := func(day models.Calendar) []models.Calendar {
+		body, err := jsoniter.Marshal(day)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, internal.RouteCalendar, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+		s.NoError(api.PutCalendarHandler(c))
+		s.Equal(http.StatusOK, c.Response().Status)
+		updated, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+		s.NoError(err)
+		return updated
+	}
+
+	updated := put(models.Calendar{MealId: meals[1].Id, Date: calendar[0].Date})
+	s.Equal(meals[1].Id, updated[0].MealId)
+	s.Empty(updated[1].LeftoverOf, "leftovers of the replaced meal are planned again")
+
+	updated = put(models.Calendar{MealId: stew.Id, Date: calendar[3].Date})
+	s.Equal(stew.Id, updated[3].MealId)
+	s.Empty(updated[3].LeftoverOf)
+	s.Equal(stew.Id, updated[4].MealId)
+	s.Equal(calendar[3].Date, updated[4].LeftoverOf)
+	s.Equal(calendar[5].MealId, updated[5].MealId)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type PantryAPI struct {
+	DB      database.Database
+	Manager managers.IPantryManager
+}
+
+func (a *PantryAPI) GetPantryItemsHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	items, err := a.Manager.GetPantryItems(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+func (a *PantryAPI) PostPantryItemHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	itemReq := &models.PantryItem{}
+	if err := c.Bind(itemReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	item, err := a.Manager.CreatePantryItem(userID, *itemReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+func (a *PantryAPI) PutPantryItemHandler(c echo.Context) error {
+	var userID, pantryItemID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:       {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamPantryItemID: {Target: &pantryItemID, Err: internal.ErrPantryItemIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	itemReq := &models.PantryItem{}
+	if err := c.Bind(itemReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	item, err := a.Manager.UpdatePantryItem(userID, pantryItemID, *itemReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+func (a *PantryAPI) DeletePantryItemHandler(c echo.Context) error {
+	var userID, pantryItemID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:       {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamPantryItemID: {Target: &pantryItemID, Err: internal.ErrPantryItemIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeletePantryItem(userID, pantryItemID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
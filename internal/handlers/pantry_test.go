@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/internal/utils"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestPostPantryItemHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:               "Create pantry item (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000016",
+			reqBody:            models.PantryItem{Ingredient: "pollo", Quantity: 500, Unit: "g", Expiry: "2030/01/10"},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:               "Create pantry item without expiry (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000016",
+			reqBody:            models.PantryItem{Ingredient: "arroz"},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:    "Create pantry item, ingredient not indicated (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000016",
+			reqBody: models.PantryItem{Ingredient: " "},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Create pantry item, wrong expiry format (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000016",
+			reqBody: models.PantryItem{Ingredient: "leche", Expiry: "10-01-2030"},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrInvalidDateFormat.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RoutePantry, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := PantryAPI{DB: *s.db, Manager: managers.NewPantryManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.reqBody)
+			err := api.PostPantryItemHandler(c)
+
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+				s.True(ok)
+				body := resp.Body.Bytes()
+
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+
+	items, err := managers.NewPantryManager(*s.db).GetPantryItems("01FN3EEB2NVFJAHAPU00000016")
+	s.NoError(err)
+	s.Len(items, 2)
+}
+
+func (s *CalendarAPITestSuite) TestDeletePantryItemHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000016"
+	item, err := managers.NewPantryManager(*s.db).CreatePantryItem(userID, models.PantryItem{Ingredient: "pollo"})
+	s.NoError(err)
+	api := PantryAPI{DB: *s.db, Manager: managers.NewPantryManager(*s.db)}
+	getEchoContext := func() echo.Context {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, internal.RoutePantryItem, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetParamNames(internal.ParamUserID, internal.ParamPantryItemID)
+		c.SetParamValues(userID, item.Id)
+		return c
+	}
+	c := getEchoContext()
+	s.NoError(api.DeletePantryItemHandler(c))
+	s.Equal(http.StatusNoContent, c.Response().Status)
+	c = getEchoContext()
+	s.Error(api.DeletePantryItemHandler(c))
+	s.Equal(http.StatusNotFound, c.Response().Status)
+
+	items, err := repositories.NewSQLitePantryRepository(s.db).GetPantryItems(userID)
+	s.NoError(err)
+	s.Empty(items)
+}
+
+func (s *CalendarAPITestSuite) TestPantryScore() {
+	today := time.Now()
+	tools := utils.NewCalendarToolsManager().WithPreferences(utils.Preferences{Pantry: []models.PantryItem{
+		{Ingredient: "pollo", Expiry: today.Format("2006/01/02")},
+		{Ingredient: "arroz"},
+		{Ingredient: "leche", Expiry: today.AddDate(0, 0, -1).Format("2006/01/02")},
+	}})
+	chicken := &models.MealToFront{Ingredients: []string{"2 pechugas de pollo", "limón"}}
+	rice := &models.MealToFront{Ingredients: []string{"200 g de arroz"}}
+	pudding := &models.MealToFront{Ingredients: []string{"leche", "azúcar"}}
+
+	s.InDelta(2.4, tools.PantryScore(chicken, today), 1e-9, "expiring today")
+	s.InDelta(0, tools.PantryScore(chicken, today.AddDate(0, 0, 1)), 1e-9, "expired by then")
+	s.InDelta(0.6, tools.PantryScore(rice, today.AddDate(0, 0, 10)), 1e-9, "without expiry")
+	s.InDelta(0, tools.PantryScore(pudding, today), 1e-9, "already expired")
+}
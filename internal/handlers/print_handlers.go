@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+	"strconv"
+)
+
+const mimeApplicationPDF = "application/pdf"
+
+type PrintAPI struct {
+	DB      database.Database
+	Manager managers.IPrintManager
+}
+
+// GetCalendarPrintHandler returns the weeks of the calendar ready to print, as
+// HTML or PDF as the format query param says, with the number of weeks and the
+// list to add given by the weeks and list query params.
+func (a *PrintAPI) GetCalendarPrintHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	options := models.PrintOptions{Format: c.QueryParam(internal.QueryFormat), List: c.QueryParam(internal.QueryPrintList)}
+	if weeks := c.QueryParam(internal.QueryWeeks); weeks != "" {
+		var err error
+		if options.Weeks, err = strconv.Atoi(weeks); err != nil || options.Weeks < 1 {
+			return internal.NewErrorResponse(c, internal.ErrInvalidPrintOptions)
+		}
+	}
+	data, err := a.Manager.PrintCalendar(userID, options)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if options.Format == models.PrintPDF {
+		c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="menu.pdf"`)
+		return c.Blob(http.StatusOK, mimeApplicationPDF, data)
+	}
+	return c.HTMLBlob(http.StatusOK, data)
+}
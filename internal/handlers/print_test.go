@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (s *CalendarAPITestSuite) TestGetCalendarPrintHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000024"
+	lentils := models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002401", Name: "Lentejas con chorizo", Ingredients: []string{"300 g de lentejas", "1 chorizo"}}
+	chicken := models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002402", Name: "Pollo & patatas", Ingredients: []string{"1 pollo", "patatas"}}
+	gone := models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002403", Name: "Sopa de ajo"}
+	monday := currentMonday()
+	date := func(days int) string { return monday.AddDate(0, 0, days).Format("2006/01/02") }
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(windowCalendar(userID,
+		models.Calendar{UserId: userID, MealId: lentils.Id, Name: lentils.Name, Date: date(0), Servings: 3},
+		models.Calendar{UserId: userID, MealId: lentils.Id, Name: lentils.Name, Date: date(1), Servings: 3, LeftoverOf: date(0)},
+		models.Calendar{UserId: userID, MealId: chicken.Id, Name: chicken.Name, Date: date(7), Servings: 2},
+		models.Calendar{UserId: userID, MealId: gone.Id, Name: gone.Name, Date: date(8), Servings: 2},
+	)))
+	api := PrintAPI{DB: *s.db, Manager: managers.NewPrintManager(*s.db)}
+	get := func(query string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, internal.RouteCalendarPrint+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		return c, rec
+	}
+
+	s.httpMock.On("GetMeal", userID, lentils.Id).Return(lentils, nil).Once()
+	s.httpMock.On("GetMeal", userID, chicken.Id).Return(chicken, nil).Once()
+	s.httpMock.On("GetMeal", userID, gone.Id).Return(models.MealToFront{}, internal.ErrMealNotFound).Once()
+	c, rec := get("?weeks=2&list=ingredients")
+	s.NoError(api.GetCalendarPrintHandler(c))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	body := rec.Body.String()
+	s.Contains(body, "Menú del "+monday.Format("02/01/2006")+" al "+monday.AddDate(0, 0, 13).Format("02/01/2006"))
+	s.Equal(2, strings.Count(body, `<table class="week">`))
+	s.Contains(body, "<th>Lunes "+monday.Format("02/01")+"</th>")
+	s.Contains(body, `<div class="leftover">Lentejas con chorizo (sobras)</div>`)
+	s.Contains(body, "3 raciones")
+	s.Contains(body, "Sin comida")
+	s.Contains(body, "Pollo &amp; patatas")
+	s.Contains(body, "<h1>Ingredientes</h1>")
+	s.Contains(body, "<li>300 g de lentejas</li>")
+	s.Equal(1, strings.Count(body, "<h2>Lentejas con chorizo</h2>"), "the meals are listed once")
+	s.Contains(body, "<h2>Sopa de ajo</h2>\n<p class=\"empty\">Ingredientes no disponibles</p>", "the meals not fetched are marked")
+
+	s.httpMock.On("GetMeal", userID, lentils.Id).Return(lentils, nil).Once()
+	c, rec = get("?format=pdf&list=shopping")
+	s.NoError(api.GetCalendarPrintHandler(c))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("application/pdf", rec.Header().Get(echo.HeaderContentType))
+	body = rec.Body.String()
+	s.True(strings.HasPrefix(body, "%PDF-1.4\n"))
+	s.True(strings.HasSuffix(body, "%%EOF\n"))
+	s.Contains(body, "(Lentejas con chorizo) Tj")
+	s.Contains(body, "(Lista de la compra) Tj")
+	s.Contains(body, "(- 300 g lentejas \\(Lentejas con chorizo\\)) Tj")
+	s.NotContains(body, "Pollo", "one week is printed by default")
+
+	for _, query := range []string{"?weeks=5", "?weeks=none", "?format=docx", "?list=recipes"} {
+		c, rec = get(query)
+		s.Error(api.GetCalendarPrintHandler(c))
+		s.Equal(http.StatusBadRequest, rec.Code, query)
+		errorReturned := new(internal.ErrorResponse)
+		s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), errorReturned))
+	}
+}
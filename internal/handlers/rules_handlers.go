@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type RuleAPI struct {
+	DB      database.Database
+	Manager managers.IRuleManager
+}
+
+func (a *RuleAPI) GetRulesHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	rules, err := a.Manager.GetRules(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, rules)
+}
+
+func (a *RuleAPI) PostRuleHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	ruleReq := &models.RecurringRule{}
+	if err := c.Bind(ruleReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	rule, err := a.Manager.CreateRule(userID, *ruleReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, rule)
+}
+
+func (a *RuleAPI) PutRuleHandler(c echo.Context) error {
+	var userID, ruleID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamRuleID: {Target: &ruleID, Err: internal.ErrRuleIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	ruleReq := &models.RecurringRule{}
+	if err := c.Bind(ruleReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	rule, err := a.Manager.UpdateRule(userID, ruleID, *ruleReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, rule)
+}
+
+func (a *RuleAPI) DeleteRuleHandler(c echo.Context) error {
+	var userID, ruleID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamRuleID: {Target: &ruleID, Err: internal.ErrRuleIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteRule(userID, ruleID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
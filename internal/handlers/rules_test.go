@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestPostRuleHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:   "Create rule (ok)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.RecurringRule{
+				MealId: "01FN3EEB2NVFJAHAPM00000003",
+				Rule:   "FREQ=WEEKLY;BYDAY=FR",
+			},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:   "Create rule, invalid rule (400)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.RecurringRule{
+				MealId: "01FN3EEB2NVFJAHAPM00000003",
+				Rule:   "FREQ=FORTNIGHTLY",
+			},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrInvalidRule.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name: "Create rule, userId not indicated (400)",
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrUserIDNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RouteRules, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := RuleAPI{DB: *s.db, Manager: managers.NewRuleManager(*s.db)}
+			s.httpMock.On("GetMeal", t.userID, "01FN3EEB2NVFJAHAPM00000003").Return(models.MealToFront{Name: "meal3"}, nil)
+
+			c := getEchoContext(t.userID, t.reqBody)
+			err := api.PostRuleHandler(c)
+
+			resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+			s.True(ok)
+			body := resp.Body.Bytes()
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			} else {
+				rule := new(models.RecurringRule)
+				s.NoError(jsoniter.Unmarshal(body, rule))
+				s.NotEmpty(rule.Id)
+				s.Equal(time.Now().Format("2006/01/02"), rule.Start)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerWithRules() {
+	userID := "01FN3EEB2NVFJAHAPU00000001"
+	s.NoError(repositories.NewSQLiteRuleRepository(s.db).CreateRule(models.RecurringRule{
+		Id:     "01FN3EEB2NVFJAHAPR00000001",
+		UserId: userID,
+		MealId: mealsDb[2].Id,
+		Rule:   "FREQ=DAILY;INTERVAL=2",
+		Start:  time.Now().Format("2006/01/02"),
+	}))
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(mealsDb, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PostCalendarHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	for i, day := range calendar {
+		if i%2 == 0 {
+			s.Equal(mealsDb[2].Id, day.MealId)
+		} else {
+			s.NotEqual(mealsDb[2].Id, day.MealId)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/internal/utils"
+	"fmt"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestPutSettingsHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:   "Update settings (ok)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.UserSettings{
+				DailyKcalMin:  400,
+				DailyKcalMax:  1200,
+				WeeklyKcalMin: 4000,
+				WeeklyKcalMax: 6000,
+			},
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:   "Update settings, max lower than min (400)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.UserSettings{
+				WeeklyKcalMin: 6000,
+				WeeklyKcalMax: 4000,
+			},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Update settings, unknown hemisphere (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.UserSettings{Hemisphere: "este"},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Update settings, prep time not for every weekday (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.UserSettings{MaxPrepTime: models.IntList{20, 20, 20}},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name: "Update settings, userId not indicated (400)",
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrUserIDNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, internal.RouteSettings, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := SettingsAPI{DB: *s.db, Manager: managers.NewSettingsManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.reqBody)
+			err := api.PutSettingsHandler(c)
+
+			resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+			s.True(ok)
+			body := resp.Body.Bytes()
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			} else {
+				settings := new(models.UserSettings)
+				s.NoError(jsoniter.Unmarshal(body, settings))
+				s.Equal(t.userID, settings.UserId)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
+
+func (s *CalendarAPITestSuite) TestGetCalendarHandlerWithSummary() {
+	userID := "01FN3EEB2NVFJAHAPU00000003"
+	today := time.Now()
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	var calendar []models.Calendar
+	for i := 0; i < 28; i++ {
+		calendar = append(calendar, models.Calendar{
+			UserId: userID,
+			MealId: mealsDb[i%len(mealsDb)].Id,
+			Name:   mealsDb[i%len(mealsDb)].Name,
+			Date:   monday.AddDate(0, 0, i).Format("2006/01/02"),
+			Kcal:   1000,
+		})
+	}
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(calendar))
+	s.NoError(repositories.NewSQLiteSettingsRepository(s.db).SaveSettings(models.UserSettings{
+		UserId:        userID,
+		WeeklyKcalMin: 7500,
+		WeeklyKcalMax: 9000,
+	}))
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(mealsDb, nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, internal.RouteCalendar+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+
+		api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+		s.NoError(api.GetCalendarHandler(c))
+		s.Equal(http.StatusOK, c.Response().Status)
+		return rec
+	}
+
+	summary := new(models.CalendarSummaryResponse)
+	s.NoError(jsoniter.Unmarshal(get("?summary=true").Body.Bytes(), summary))
+	s.Len(summary.Calendar, 28)
+	s.NotEmpty(summary.Weeks)
+	first := summary.Weeks[0]
+	s.Equal(monday.Format("2006/01/02"), first.From)
+	s.Equal(7, first.Days)
+	s.Equal(7000, first.Kcal)
+	s.Equal(-500, first.KcalDeviation)
+
+	var bare []models.Calendar
+	s.NoError(jsoniter.Unmarshal(get("").Body.Bytes(), &bare), "the bare calendar is returned by default")
+	s.Len(bare, 28)
+	s.NoError(jsoniter.Unmarshal(get("?summary=false").Body.Bytes(), &bare))
+	s.Len(bare, 28)
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerIngredientVariety() {
+	userID := "01FN3EEB2NVFJAHAPU00000004"
+	mainIngredients := []string{"pollo", "ternera"}
+	var meals []*models.MealToFront
+	for i := 0; i < 6; i++ {
+		meals = append(meals, &models.MealToFront{
+			Id:          fmt.Sprintf("01FN3EEB2NVFJAHAPM0000010%d", i),
+			UserId:      userID,
+			Name:        fmt.Sprintf("meal%d", i),
+			Type:        models.Normal,
+			Ingredients: []string{mainIngredients[i%2], fmt.Sprintf("verdura%d", i)},
+		})
+	}
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PostCalendarHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	mainOf := func(mealId string) string {
+		for _, m := range meals {
+			if m.Id == mealId {
+				return m.Ingredients[0]
+			}
+		}
+		return ""
+	}
+	for i := 1; i < len(calendar); i++ {
+		s.NotEqual(mainOf(calendar[i-1].MealId), mainOf(calendar[i].MealId))
+	}
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerSouthernHemisphere() {
+	userID := "01FN3EEB2NVFJAHAPU00000006"
+	settings := models.NewUserSettings(userID)
+	settings.Hemisphere = models.South
+	s.NoError(repositories.NewSQLiteSettingsRepository(s.db).SaveSettings(settings))
+
+	seasons := []string{"invierno", "primavera", "verano", "otoño"}
+	var meals []*models.MealToFront
+	for i := 0; i < 8; i++ {
+		meals = append(meals, &models.MealToFront{
+			Id:      fmt.Sprintf("01FN3EEB2NVFJAHAPM0000030%d", i),
+			UserId:  userID,
+			Name:    fmt.Sprintf("meal%d", i),
+			Type:    models.Normal,
+			Seasons: []string{seasons[i%4]},
+		})
+	}
+	today := time.Now()
+	requested := func(requested []string) bool {
+		first := utils.SeasonOf(today, models.South)
+		last := utils.SeasonOf(today.AddDate(0, 0, 28), models.South)
+		return len(requested) > 0 && requested[0] == first && requested[len(requested)-1] == last
+	}
+	s.httpMock.On("GetAllMeals", userID, mock.MatchedBy(requested)).Return(meals, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PostCalendarHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	for _, day := range calendar {
+		date, errDate := time.Parse("2006/01/02", day.Date)
+		s.NoError(errDate)
+		for _, m := range meals {
+			if m.Id == day.MealId {
+				s.Equal(utils.SeasonOf(date, models.South), m.Seasons[0])
+			}
+		}
+	}
+	s.Equal("verano", utils.SeasonOf(time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC), models.South))
+	s.Equal("invierno", utils.SeasonOf(time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC), models.North))
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerPrepTime() {
+	userID := "01FN3EEB2NVFJAHAPU00000008"
+	settings := models.NewUserSettings(userID)
+	settings.MaxPrepTime = models.IntList{0, 20, 20, 20, 20, 20, 20}
+	s.NoError(repositories.NewSQLiteSettingsRepository(s.db).SaveSettings(settings))
+
+	prepTimes := []int{15, 90, 0}
+	var meals []*models.MealToFront
+	for i := 0; i < 9; i++ {
+		meals = append(meals, &models.MealToFront{
+			Id:       fmt.Sprintf("01FN3EEB2NVFJAHAPM0000050%d", i),
+			UserId:   userID,
+			Name:     fmt.Sprintf("meal%d", i),
+			Type:     models.Normal,
+			PrepTime: prepTimes[i%3],
+		})
+	}
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PostCalendarHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	for _, day := range calendar {
+		date, _ := time.Parse("2006/01/02", day.Date)
+		if date.Weekday() == time.Sunday {
+			continue
+		}
+		for _, m := range meals {
+			if m.Id == day.MealId {
+				s.NotEqual(90, m.PrepTime, day.Date)
+			}
+		}
+	}
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerDefaultServings() {
+	userID := "01FN3EEB2NVFJAHAPU00000014"
+	settings := models.NewUserSettings(userID)
+	settings.DefaultServings = 4
+	s.NoError(repositories.NewSQLiteSettingsRepository(s.db).SaveSettings(settings))
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(mealsDb, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PostCalendarHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	for _, day := range calendar {
+		s.Equal(4, day.Servings, day.Date)
+	}
+}
+
+func (s *CalendarAPITestSuite) TestSpecialMealOnHighServings() {
+	settings := models.NewUserSettings("01FN3EEB2NVFJAHAPU00000014")
+	tools := utils.NewCalendarToolsManager().WithPreferences(utils.Preferences{Settings: settings})
+	occasional := &models.MealToFront{Type: models.Ocasional}
+	normal := &models.MealToFront{Type: models.Normal}
+	saturday := time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)
+
+	s.InDelta(2.10, tools.SpecialMeal(occasional, 0, saturday, 2), 1e-9, "usual servings")
+	s.InDelta(2.10+settings.ServingsBonus, tools.SpecialMeal(occasional, 0, saturday, 6), 1e-9, "guests")
+	s.InDelta(0, tools.SpecialMeal(normal, 0, saturday, 6), 1e-9, "not occasional")
+
+	settings.HighServings = 8
+	tools = utils.NewCalendarToolsManager().WithPreferences(utils.Preferences{Settings: settings})
+	s.InDelta(2.10, tools.SpecialMeal(occasional, 0, saturday, 6), 1e-9, "below the high servings")
+}
+
+func (s *CalendarAPITestSuite) TestReuseScore() {
+	settings := models.NewUserSettings("01FN3EEB2NVFJAHAPU00000017")
+	settings.IngredientReuse = 2
+	tools := utils.NewCalendarToolsManager().WithPreferences(utils.Preferences{Settings: settings})
+	mealsById := map[string]*models.MealToFront{
+		"01FN3EEB2NVFJAHAPM00000901": {Id: "01FN3EEB2NVFJAHAPM00000901", Ingredients: []string{"1/2 col", "zanahorias", "aceite"}},
+		"01FN3EEB2NVFJAHAPM00000902": {Id: "01FN3EEB2NVFJAHAPM00000902", Ingredients: []string{"pollo"}},
+	}
+	calendar := []models.Calendar{
+		{MealId: "01FN3EEB2NVFJAHAPM00000902", Date: "2030/01/06"},
+		{MealId: "01FN3EEB2NVFJAHAPM00000901", Date: "2030/01/07"},
+	}
+	meal := &models.MealToFront{Ingredients: []string{"col", "pollo", "sal"}}
+	thursday := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	s.InDelta(1.0, tools.ReuseScore(calendar, meal, thursday, mealsById), 1e-9, "the cabbage of monday, staples are not counted")
+	s.InDelta(0, tools.ReuseScore(calendar, meal, thursday.AddDate(0, 0, 4), mealsById), 1e-9, "next week")
+	s.Greater(tools.ReuseScore(calendar, meal, thursday, mealsById)+tools.IngredientScore(calendar, meal, thursday, mealsById), 0.0)
+
+	settings.IngredientReuse = 0
+	tools = utils.NewCalendarToolsManager().WithPreferences(utils.Preferences{Settings: settings})
+	s.InDelta(0, tools.ReuseScore(calendar, meal, thursday, mealsById), 1e-9, "disabled")
+}
+
+func (s *CalendarAPITestSuite) TestPatchSettingsHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000026"
+	api := SettingsAPI{DB: *s.db, Manager: managers.NewSettingsManager(*s.db)}
+	send := func(method, body string) (*models.UserSettings, error) {
+		e := echo.New()
+		req := httptest.NewRequest(method, internal.RouteSettings, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		var err error
+		if method == http.MethodPatch {
+			err = api.PatchSettingsHandler(c)
+		} else {
+			err = api.PutSettingsHandler(c)
+		}
+		settings := new(models.UserSettings)
+		s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), settings))
+		return settings, err
+	}
+
+	settings, err := send(http.MethodPatch, `{"weekly_kcal_min":4000,"weekly_kcal_max":6000}`)
+	s.NoError(err)
+	s.Equal(6000, settings.WeeklyKcalMax)
+	s.Equal(models.NewUserSettings(userID).IngredientSpacing, settings.IngredientSpacing, "the settings not sent keep their value")
+	s.Equal(models.North, settings.Hemisphere)
+
+	settings, err = send(http.MethodPatch, `{"hemisphere":"sur"}`)
+	s.NoError(err)
+	s.Equal(models.South, settings.Hemisphere)
+	s.Equal(4000, settings.WeeklyKcalMin)
+
+	_, err = send(http.MethodPatch, `{"weekly_kcal_max":3000}`)
+	s.Error(err, "the patched settings are validated")
+	_, err = send(http.MethodPatch, `{"hemisphere":`)
+	s.Error(err)
+
+	settings, err = send(http.MethodPut, `{"daily_kcal_max":900}`)
+	s.NoError(err)
+	s.Equal(900, settings.DailyKcalMax)
+	s.Zero(settings.WeeklyKcalMin, "put replaces every setting")
+	s.Zero(settings.IngredientSpacing)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/utils"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+	"strings"
+)
+
+const (
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+	formatText     = "text"
+
+	mimeTextMarkdown = "text/markdown; charset=UTF-8"
+)
+
+type ShoppingListAPI struct {
+	DB      database.Database
+	Manager managers.IShoppingListManager
+}
+
+// GetShoppingListHandler returns the shopping list as JSON, or rendered as
+// Markdown or plain text when asked by the format query param or the Accept
+// header.
+func (a *ShoppingListAPI) GetShoppingListHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	format, err := responseFormat(c)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	list, err := a.Manager.GetShoppingList(userID, c.QueryParam(internal.QueryFrom), c.QueryParam(internal.QueryTo))
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	switch format {
+	case formatMarkdown:
+		return c.Blob(http.StatusOK, mimeTextMarkdown, []byte(utils.ShoppingListMarkdown(list)))
+	case formatText:
+		return c.String(http.StatusOK, utils.ShoppingListMarkdown(list))
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// responseFormat returns the format asked by the format query param, or else by
+// the Accept header. JSON is the default one.
+func responseFormat(c echo.Context) (string, error) {
+	switch format := strings.ToLower(c.QueryParam(internal.QueryFormat)); format {
+	case "":
+	case formatJSON, formatMarkdown, formatText:
+		return format, nil
+	case "md":
+		return formatMarkdown, nil
+	default:
+		return "", internal.ErrUnsupportedFormat
+	}
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+	switch {
+	case strings.Contains(accept, "text/markdown"):
+		return formatMarkdown, nil
+	case strings.Contains(accept, echo.MIMETextPlain):
+		return formatText, nil
+	}
+	return formatJSON, nil
+}
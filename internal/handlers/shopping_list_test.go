@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/config"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/internal/utils"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (s *CalendarAPITestSuite) TestGetShoppingListHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000015"
+	paella := models.MealToFront{Name: "paella", Ingredients: []string{"200 g de arroz", "Gambas", "tomates"}}
+	salad := models.MealToFront{Name: "ensalada", Ingredients: []string{"Tomate", "lechuga", "2 huevos"}}
+	risotto := models.MealToFront{Name: "risotto", Ingredients: []string{"0,3kg arroz", "Queso parmesano", "caldo de verduras", "tomate frito"}}
+	monday := currentMonday()
+	date := func(days int) string { return monday.AddDate(0, 0, days).Format("2006/01/02") }
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(windowCalendar(userID,
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00000801", Name: paella.Name, Date: date(0)},
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00000801", Name: paella.Name, Date: date(1), LeftoverOf: date(0)},
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00000802", Name: salad.Name, Date: date(2)},
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00000803", Name: risotto.Name, Date: date(3)},
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00000804", Name: "gone", Date: date(4)},
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00000802", Name: salad.Name, Date: date(13)},
+	)))
+	s.httpMock.On("GetMeal", userID, "01FN3EEB2NVFJAHAPM00000801").Return(paella, nil)
+	s.httpMock.On("GetMeal", userID, "01FN3EEB2NVFJAHAPM00000802").Return(salad, nil)
+	s.httpMock.On("GetMeal", userID, "01FN3EEB2NVFJAHAPM00000803").Return(risotto, nil)
+	s.httpMock.On("GetMeal", userID, "01FN3EEB2NVFJAHAPM00000804").Return(models.MealToFront{}, internal.ErrMealNotFound)
+
+	get := func(query, accept string) (echo.Context, error) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, internal.RouteShoppingList+"?"+query, nil)
+		if accept != "" {
+			req.Header.Set(echo.HeaderAccept, accept)
+		}
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		api := ShoppingListAPI{DB: *s.db, Manager: managers.NewShoppingListManager(*s.db)}
+		return c, api.GetShoppingListHandler(c)
+	}
+	body := func(c echo.Context) []byte {
+		return c.Response().Writer.(*httptest.ResponseRecorder).Body.Bytes()
+	}
+
+	c, err := get("from="+date(0)+"&to="+date(6), "")
+	s.NoError(err)
+	s.Equal(http.StatusOK, c.Response().Status)
+	var list models.ShoppingList
+	s.NoError(jsoniter.Unmarshal(body(c), &list))
+	s.Equal([]string{"gone"}, list.Missing)
+	items := map[string]models.ShoppingItem{}
+	itemCategories := map[string]string{}
+	var categories []string
+	for _, category := range list.Categories {
+		categories = append(categories, category.Name)
+		for _, item := range category.Items {
+			items[item.Name] = item
+			itemCategories[item.Name] = category.Name
+		}
+	}
+	s.Equal([]string{"Frutas y verduras", "Pescado y marisco", "Lácteos y huevos", "Despensa"}, categories)
+	s.Equal(500.0, items["arroz"].Quantity)
+	s.Equal("g", items["arroz"].Unit)
+	s.Equal([]string{"paella", "risotto"}, items["arroz"].Meals)
+	s.Equal(2, items["tomates"].Count, "leftovers are not counted and plurals are merged")
+	s.Equal(2.0, items["huevos"].Quantity)
+	s.Len(items, 8)
+	s.Equal("Despensa", itemCategories["tomate frito"], "not a tomato")
+
+	c, err = get("from="+date(0)+"&to="+date(6), "text/markdown")
+	s.NoError(err)
+	s.Equal(http.StatusOK, c.Response().Status)
+	s.True(strings.HasPrefix(c.Response().Header().Get(echo.HeaderContentType), "text/markdown"))
+	s.Contains(string(body(c)), "## Despensa\n\n- [ ] 500 g arroz (paella, risotto)\n")
+
+	c, err = get("from="+date(6)+"&to="+date(0), "")
+	s.Error(err)
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+
+	c, err = get("format=pdf", "")
+	s.Error(err)
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+}
+
+func (s *CalendarAPITestSuite) TestGetShoppingListHandlerMealsUnreachable() {
+	userID := "01FN3EEB2NVFJAHAPU00000025"
+	monday := currentMonday().Format("2006/01/02")
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(windowCalendar(userID,
+		models.Calendar{UserId: userID, MealId: "01FN3EEB2NVFJAHAPM00002501", Name: "paella", Date: monday},
+	)))
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	mealsURL := config.Config.MealsURL
+	defer func() { config.Config.MealsURL = mealsURL }()
+	config.Config.MealsURL = server.URL + "/"
+	managers.Microservices = &utils.Endpoints{}
+
+	_, err := managers.Microservices.GetMeal(userID, "01FN3EEB2NVFJAHAPM00002501")
+	s.Equal(internal.ErrReturningMeal, err)
+	_, err = managers.Microservices.GetAllMeals(userID, nil)
+	s.Equal(internal.ErrReturningAllMeals, err)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, internal.RouteShoppingList+"?from="+monday, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+	api := ShoppingListAPI{DB: *s.db, Manager: managers.NewShoppingListManager(*s.db)}
+	s.NoError(api.GetShoppingListHandler(c))
+	s.Equal(http.StatusOK, rec.Code)
+	var list models.ShoppingList
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), &list))
+	s.Equal([]string{"paella"}, list.Missing)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type SpecialDateAPI struct {
+	DB      database.Database
+	Manager managers.ISpecialDateManager
+}
+
+func (a *SpecialDateAPI) GetSpecialDatesHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	dates, err := a.Manager.GetSpecialDates(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, dates)
+}
+
+func (a *SpecialDateAPI) PostSpecialDateHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	dateReq := &models.SpecialDate{}
+	if err := c.Bind(dateReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	date, err := a.Manager.CreateSpecialDate(userID, *dateReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, date)
+}
+
+func (a *SpecialDateAPI) DeleteSpecialDateHandler(c echo.Context) error {
+	var userID, dateID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:        {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamSpecialDateID: {Target: &dateID, Err: internal.ErrSpecialDateIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteSpecialDate(userID, dateID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ImportSpecialDatesHandler reads the iCalendar file from the "file" field of a
+// multipart form, or from the body of the request otherwise.
+func (a *SpecialDateAPI) ImportSpecialDatesHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	ics, err := requestFile(c)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	defer ics.Close()
+	dates, err := a.Manager.ImportSpecialDates(userID, ics)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, dates)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/internal/utils"
+	"calendar/pkg/holidays"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+const specialDatesICS = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:19900314\r\nRRULE:FREQ=YEARLY\r\nSUMMARY:Cumpleaños de Ana\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20240920\r\nSUMMARY:Fiesta local\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func (s *CalendarAPITestSuite) TestPostSpecialDateHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:               "Create yearly special date (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000002",
+			reqBody:            models.SpecialDate{Date: "03/14", Name: "Cumpleaños"},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:               "Create easter special date (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000002",
+			reqBody:            models.SpecialDate{Date: "easter+1", Name: "Lunes de Pascua"},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:    "Create special date, wrong date (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.SpecialDate{Date: "14/03"},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrInvalidSpecialDate.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Create special date, date not indicated (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.SpecialDate{Name: "Cumpleaños"},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RouteSpecialDates, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := SpecialDateAPI{DB: *s.db, Manager: managers.NewSpecialDateManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.reqBody)
+			err := api.PostSpecialDateHandler(c)
+
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+				s.True(ok)
+				body := resp.Body.Bytes()
+
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
+
+func (s *CalendarAPITestSuite) TestImportSpecialDatesHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000002"
+	repository := repositories.NewSQLiteSpecialDateRepository(s.db)
+	s.NoError(repository.CreateSpecialDate(models.SpecialDate{Id: "01FN3EEB2NVFJAHAPD00000001", UserId: userID, Date: "12/31", Source: models.SpecialDateCustom}))
+	api := SpecialDateAPI{DB: *s.db, Manager: managers.NewSpecialDateManager(*s.db)}
+
+	importICS := func(body *bytes.Buffer, contentType string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RouteSpecialDateICS, body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userID)
+		return c, rec
+	}
+
+	c, rec := importICS(bytes.NewBufferString(specialDatesICS), "text/calendar")
+	s.NoError(api.ImportSpecialDatesHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+	var imported []models.SpecialDate
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), &imported))
+	s.Len(imported, 2)
+	s.Equal("03/14", imported[0].Date)
+	s.Equal("2024/09/20", imported[1].Date)
+
+	form := &bytes.Buffer{}
+	writer := multipart.NewWriter(form)
+	file, err := writer.CreateFormFile("file", "dates.ics")
+	s.NoError(err)
+	_, err = file.Write([]byte(specialDatesICS))
+	s.NoError(err)
+	s.NoError(writer.Close())
+	c, _ = importICS(form, writer.FormDataContentType())
+	s.NoError(api.ImportSpecialDatesHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	dates, err := repository.GetSpecialDates(userID)
+	s.NoError(err)
+	s.Len(dates, 3, "the second import replaces the first one and keeps the custom dates")
+
+	c, rec = importICS(bytes.NewBufferString("BEGIN:VCALENDAR\r\n"), "text/calendar")
+	s.Error(api.ImportSpecialDatesHandler(c))
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+	errorReturned := new(internal.ErrorResponse)
+	s.NoError(jsoniter.Unmarshal(rec.Body.Bytes(), errorReturned))
+	s.Equal(internal.ErrInvalidICS.Error(), errorReturned.Err.Message)
+}
+
+func (s *CalendarAPITestSuite) TestPutSettingsHandlerHolidays() {
+	api := SettingsAPI{DB: *s.db, Manager: managers.NewSettingsManager(*s.db)}
+	put := func(settings models.UserSettings) echo.Context {
+		body, err := jsoniter.Marshal(settings)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, internal.RouteSettings, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues("01FN3EEB2NVFJAHAPU00000002")
+		return c
+	}
+
+	c := put(models.UserSettings{HolidayCountry: "ES", HolidayRegion: "MD"})
+	s.NoError(api.PutSettingsHandler(c))
+	s.Equal(http.StatusOK, c.Response().Status)
+
+	c = put(models.UserSettings{HolidayCountry: "ES", HolidayRegion: "XX"})
+	s.Error(api.PutSettingsHandler(c))
+	s.Equal(http.StatusBadRequest, c.Response().Status)
+}
+
+func (s *CalendarAPITestSuite) TestSpecialMealOnHolidays() {
+	national, err := holidays.Load("ES", "")
+	s.NoError(err)
+	tools := utils.NewCalendarToolsManager().WithPreferences(utils.Preferences{
+		Holidays: append(national, holidays.Holiday{Date: "03/14", Name: "Cumpleaños"}),
+	})
+	occasional := &models.MealToFront{Type: models.Ocasional}
+	date := func(s string) time.Time {
+		t, _ := time.Parse("2006/01/02", s)
+		return t
+	}
+
+	s.Equal(2.10, tools.SpecialMeal(occasional, 0, date("2024/05/01"), 2), "public holiday on a wednesday")
+	s.Equal(2.10, tools.SpecialMeal(occasional, 0, date("2024/03/29"), 2), "good friday")
+	s.Equal(2.10, tools.SpecialMeal(occasional, 0, date("2024/03/14"), 2), "birthday on a thursday")
+	s.Equal(2.10, tools.SpecialMeal(occasional, 0, date("2024/05/04"), 2), "saturday")
+	s.Equal(-2.9, tools.SpecialMeal(occasional, 0, date("2024/05/02"), 2), "regular thursday")
+}
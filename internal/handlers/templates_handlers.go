@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type TemplateAPI struct {
+	DB              database.Database
+	Manager         managers.ITemplateManager
+	CalendarManager managers.ICalendarManager
+}
+
+func (a *TemplateAPI) GetTemplatesHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	templates, err := a.Manager.GetTemplates(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, templates)
+}
+
+func (a *TemplateAPI) PostTemplateHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	templateReq := &models.Template{}
+	if err := c.Bind(templateReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	template, err := a.Manager.CreateTemplate(userID, *templateReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, template)
+}
+
+func (a *TemplateAPI) GetTemplateHandler(c echo.Context) error {
+	var userID, templateID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:     {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamTemplateID: {Target: &templateID, Err: internal.ErrTemplateIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	template, err := a.Manager.GetTemplate(userID, templateID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+func (a *TemplateAPI) PutTemplateHandler(c echo.Context) error {
+	var userID, templateID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:     {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamTemplateID: {Target: &templateID, Err: internal.ErrTemplateIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	templateReq := &models.Template{}
+	if err := c.Bind(templateReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	template, err := a.Manager.UpdateTemplate(userID, templateID, *templateReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+func (a *TemplateAPI) DeleteTemplateHandler(c echo.Context) error {
+	var userID, templateID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:     {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamTemplateID: {Target: &templateID, Err: internal.ErrTemplateIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteTemplate(userID, templateID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *TemplateAPI) ApplyTemplateHandler(c echo.Context) error {
+	var userID, templateID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:     {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamTemplateID: {Target: &templateID, Err: internal.ErrTemplateIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	apply := &models.ApplyTemplate{}
+	if err := c.Bind(apply); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	calendar, err := a.Manager.ApplyTemplate(userID, templateID, *apply)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	finalCal, err := a.CalendarManager.GetFrontCalendar(calendar)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, finalCal)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestPostTemplateHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:   "Create template (ok)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.Template{
+				Name: "standard week",
+				Days: []models.TemplateDay{
+					{Weekday: 1, MealId: "01FN3EEB2NVFJAHAPM00000003"},
+					{Weekday: 5, MealType: models.Ocasional},
+				},
+			},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:   "Create template with a user defined meal type (ok)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.Template{
+				Name: "batch cooking week",
+				Days: []models.TemplateDay{{Weekday: 0, MealType: "batch-cook"}},
+			},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:   "Create template, wrong weekday (400)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.Template{
+				Name: "standard week",
+				Days: []models.TemplateDay{{Weekday: 9, MealId: "01FN3EEB2NVFJAHAPM00000003"}},
+			},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name: "Create template, userId not indicated (400)",
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrUserIDNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RouteTemplates, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := TemplateAPI{DB: *s.db, Manager: managers.NewTemplateManager(*s.db), CalendarManager: managers.NewCalendarManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.reqBody)
+			err := api.PostTemplateHandler(c)
+
+			resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+			s.True(ok)
+			body := resp.Body.Bytes()
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			} else {
+				template := new(models.Template)
+				s.NoError(jsoniter.Unmarshal(body, template))
+				s.NotEmpty(template.Id)
+				s.Len(template.Days, len(t.reqBody.(models.Template).Days))
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
+
+func (s *CalendarAPITestSuite) TestApplyTemplateHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000035"
+	// The calendar is a week behind, so it is moved before the template is applied.
+	lastMonday := currentMonday().AddDate(0, 0, -7)
+	var calendar []models.Calendar
+	for i := 0; i < 28; i++ {
+		calendar = append(calendar, models.Calendar{UserId: userID, MealId: mealsDb[i%len(mealsDb)].Id, Name: mealsDb[i%len(mealsDb)].Name, Date: lastMonday.AddDate(0, 0, i).Format("2006/01/02")})
+	}
+	s.NoError(repositories.NewSQLiteCalendarRepository(s.db).CreateCalendar(calendar))
+	template := models.Template{
+		Id:     "01FN3EEB2NVFJAHAPT00000001",
+		UserId: userID,
+		Name:   "standard week",
+		Days: []models.TemplateDay{
+			{Weekday: int(time.Now().Weekday()), MealId: mealsDb[2].Id},
+		},
+	}
+	s.NoError(repositories.NewSQLiteTemplateRepository(s.db).CreateTemplate(template))
+
+	tests := []struct {
+		name               string
+		templateID         string
+		reqBody            interface{}
+		expectedMeal       string
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:               "Apply template (ok)",
+			templateID:         template.Id,
+			reqBody:            models.ApplyTemplate{From: time.Now().Format("2006/01/02")},
+			expectedMeal:       mealsDb[2].Id,
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:       "Apply template, template not found (404)",
+			templateID: "01FN3EEB2NVFJAHAPT00000099",
+			reqBody:    models.ApplyTemplate{From: time.Now().Format("2006/01/02")},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusNotFound,
+					Message: internal.ErrTemplateNotFound.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusNotFound,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(templateId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RouteTemplateApply, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID, internal.ParamTemplateID)
+		c.SetParamValues(userID, templateId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := TemplateAPI{DB: *s.db, Manager: managers.NewTemplateManager(*s.db), CalendarManager: managers.NewCalendarManager(*s.db)}
+			s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(mealsDb, nil).Twice()
+
+			c := getEchoContext(t.templateID, t.reqBody)
+			err := api.ApplyTemplateHandler(c)
+
+			resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+			s.True(ok)
+			body := resp.Body.Bytes()
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			} else {
+				day, errDay := repositories.NewSQLiteCalendarRepository(s.db).GetCalendarSpecificDate(userID, time.Now().Format("2006/01/02"))
+				s.NoError(errDay)
+				s.Equal(t.expectedMeal, day[0].MealId)
+				s.requireWindow(userID)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type ThemeAPI struct {
+	DB      database.Database
+	Manager managers.IThemeManager
+}
+
+func (a *ThemeAPI) GetThemesHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	themes, err := a.Manager.GetThemes(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, themes)
+}
+
+func (a *ThemeAPI) PostThemeHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	themeReq := &models.WeekdayTheme{}
+	if err := c.Bind(themeReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	theme, err := a.Manager.CreateTheme(userID, *themeReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusCreated, theme)
+}
+
+func (a *ThemeAPI) PutThemeHandler(c echo.Context) error {
+	var userID, themeID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:  {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamThemeID: {Target: &themeID, Err: internal.ErrThemeIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	themeReq := &models.WeekdayTheme{}
+	if err := c.Bind(themeReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	theme, err := a.Manager.UpdateTheme(userID, themeID, *themeReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, theme)
+}
+
+func (a *ThemeAPI) DeleteThemeHandler(c echo.Context) error {
+	var userID, themeID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:  {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamThemeID: {Target: &themeID, Err: internal.ErrThemeIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteTheme(userID, themeID); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"fmt"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (s *CalendarAPITestSuite) TestPostThemeHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:   "Create theme (ok)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.WeekdayTheme{
+				Weekday:   1,
+				Name:      "Meatless Monday",
+				Attribute: models.ThemeIngredient,
+				Value:     "carne",
+				Exclude:   true,
+				Strict:    true,
+			},
+			expectedStatusCode: http.StatusCreated,
+			wantErr:            false,
+		},
+		{
+			name:   "Create theme, wrong attribute (400)",
+			userID: "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.WeekdayTheme{
+				Weekday:   2,
+				Attribute: "color",
+				Value:     "red",
+			},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name: "Create theme, userId not indicated (400)",
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrUserIDNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, internal.RouteThemes, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID)
+		c.SetParamValues(userId)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := ThemeAPI{DB: *s.db, Manager: managers.NewThemeManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.reqBody)
+			err := api.PostThemeHandler(c)
+
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+				s.True(ok)
+				body := resp.Body.Bytes()
+
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerWithThemes() {
+	tests := []struct {
+		name         string
+		userID       string
+		theme        models.WeekdayTheme
+		expectedType string
+	}{
+		{
+			name:         "Strict theme filters the meals",
+			userID:       "01FN3EEB2NVFJAHAPU00000011",
+			theme:        models.WeekdayTheme{Attribute: models.ThemeType, Value: models.Semanal, Strict: true},
+			expectedType: models.Semanal,
+		},
+		{
+			name:   "Strict theme without meals falls back to every meal",
+			userID: "01FN3EEB2NVFJAHAPU00000012",
+			theme:  models.WeekdayTheme{Attribute: models.ThemeTag, Value: "veggie", Strict: true},
+		},
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			themesDb := repositories.NewSQLiteThemeRepository(s.db)
+			for wd := 0; wd < 7; wd++ {
+				theme := t.theme
+				theme.Id = fmt.Sprintf("01FN3EEB2NVFJAHAPH0000000%d", wd)
+				theme.UserId = t.userID
+				theme.Weekday = wd
+				s.NoError(themesDb.CreateTheme(theme))
+			}
+			s.httpMock.On("GetAllMeals", t.userID, mock.Anything).Return(mealsDb, nil).Once()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+			c.SetParamNames(internal.ParamUserID)
+			c.SetParamValues(t.userID)
+
+			api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+			s.NoError(api.PostCalendarHandler(c))
+			s.Equal(http.StatusCreated, c.Response().Status)
+
+			calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(t.userID)
+			s.NoError(err)
+			for _, day := range calendar {
+				for _, meal := range mealsDb {
+					if meal.Id == day.MealId && t.expectedType != "" {
+						s.True(strings.EqualFold(t.expectedType, meal.Type))
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"calendar/pkg/url"
+
+	"github.com/labstack/echo/v4"
+
+	"net/http"
+)
+
+type TypePolicyAPI struct {
+	DB      database.Database
+	Manager managers.ITypePolicyManager
+}
+
+func (a *TypePolicyAPI) GetTypePoliciesHandler(c echo.Context) error {
+	var userID string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID: {Target: &userID, Err: internal.ErrUserIDNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	policies, err := a.Manager.GetTypePolicies(userID)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, policies)
+}
+
+func (a *TypePolicyAPI) PutTypePolicyHandler(c echo.Context) error {
+	var userID, mealType string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:   {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamMealType: {Target: &mealType, Err: internal.ErrMealTypeNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	policyReq := &models.TypePolicy{}
+	if err := c.Bind(policyReq); err != nil {
+		return internal.NewErrorResponse(c, internal.ErrWrongBody)
+	}
+	policy, err := a.Manager.UpdateTypePolicy(userID, mealType, *policyReq)
+	if err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+func (a *TypePolicyAPI) DeleteTypePolicyHandler(c echo.Context) error {
+	var userID, mealType string
+	if err := url.ParseURLPath(c, url.PathMap{
+		internal.ParamUserID:   {Target: &userID, Err: internal.ErrUserIDNotPresent},
+		internal.ParamMealType: {Target: &mealType, Err: internal.ErrMealTypeNotPresent},
+	}); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	if err := a.Manager.DeleteTypePolicy(userID, mealType); err != nil {
+		return internal.NewErrorResponse(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/managers"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/internal/utils"
+	"fmt"
+	"github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *CalendarAPITestSuite) TestPutTypePolicyHandler() {
+	tests := []struct {
+		name               string
+		userID             string
+		mealType           string
+		reqBody            interface{}
+		expectedResp       interface{}
+		expectedStatusCode int
+		wantErr            bool
+	}{
+		{
+			name:               "Update default type policy (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000002",
+			mealType:           "Semanal",
+			reqBody:            models.TypePolicy{MinSpacing: 14},
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:               "Create new type policy (ok)",
+			userID:             "01FN3EEB2NVFJAHAPU00000002",
+			mealType:           " Batch-Cook ",
+			reqBody:            models.TypePolicy{Weekdays: models.IntList{0}, MaxCount: 1},
+			expectedStatusCode: http.StatusOK,
+			wantErr:            false,
+		},
+		{
+			name:     "Update type policy, wrong weekday (400)",
+			userID:   "01FN3EEB2NVFJAHAPU00000002",
+			mealType: "batch-cook",
+			reqBody:  models.TypePolicy{Weekdays: models.IntList{7}},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrWrongBody.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:    "Update type policy, type not indicated (400)",
+			userID:  "01FN3EEB2NVFJAHAPU00000002",
+			reqBody: models.TypePolicy{},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrMealTypeNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:     "Update type policy, blank type (400)",
+			userID:   "01FN3EEB2NVFJAHAPU00000002",
+			mealType: "   ",
+			reqBody:  models.TypePolicy{},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrMealTypeNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+		{
+			name:     "Update type policy, invalid type (400)",
+			userID:   "01FN3EEB2NVFJAHAPU00000002",
+			mealType: "batch--cook!",
+			reqBody:  models.TypePolicy{},
+			expectedResp: &internal.ErrorResponse{
+				Err: internal.ErrorBody{
+					Status:  http.StatusBadRequest,
+					Message: internal.ErrMealTypeNotPresent.Error(),
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			wantErr:            true,
+		},
+	}
+	getEchoContext := func(userId, mealType string, request interface{}) echo.Context {
+		var body []byte
+		body, err := jsoniter.Marshal(request)
+		s.NoError(err)
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, internal.RouteTypePolicy, bytes.NewBuffer(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(internal.ParamUserID, internal.ParamMealType)
+		c.SetParamValues(userId, mealType)
+		return c
+	}
+	for _, t := range tests {
+		s.Run(t.name, func() {
+			api := TypePolicyAPI{DB: *s.db, Manager: managers.NewTypePolicyManager(*s.db)}
+
+			c := getEchoContext(t.userID, t.mealType, t.reqBody)
+			err := api.PutTypePolicyHandler(c)
+
+			if t.wantErr {
+				s.Equal(t.wantErr, err != nil)
+				resp, ok := c.Response().Writer.(*httptest.ResponseRecorder)
+				s.True(ok)
+				body := resp.Body.Bytes()
+
+				errorReturned := new(internal.ErrorResponse)
+				s.NoError(jsoniter.Unmarshal(body, errorReturned))
+				s.Equal(errorReturned, t.expectedResp)
+			}
+			s.Equal(t.expectedStatusCode, c.Response().Status)
+		})
+	}
+
+	policies, err := managers.NewTypePolicyManager(*s.db).GetTypePolicies("01FN3EEB2NVFJAHAPU00000002")
+	s.NoError(err)
+	s.Len(policies, 3)
+	s.Equal("batch-cook", policies[0].Type)
+	s.Equal(models.Ocasional, policies[1].Type)
+	s.True(policies[1].Default)
+	s.Equal(models.Semanal, policies[2].Type)
+	s.Equal(14, policies[2].MinSpacing)
+	s.False(policies[2].Default)
+}
+
+func (s *CalendarAPITestSuite) TestDeleteTypePolicyHandler() {
+	userID := "01FN3EEB2NVFJAHAPU00000002"
+	s.NoError(repositories.NewSQLiteTypePolicyRepository(s.db).SaveTypePolicy(models.TypePolicy{UserId: userID, Type: models.Semanal, MinSpacing: 14}))
+	api := TypePolicyAPI{DB: *s.db, Manager: managers.NewTypePolicyManager(*s.db)}
+	deletePolicy := func(mealType string) echo.Context {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, internal.RouteTypePolicy, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetParamNames(internal.ParamUserID, internal.ParamMealType)
+		c.SetParamValues(userID, mealType)
+		return c
+	}
+
+	c := deletePolicy(models.Semanal)
+	s.NoError(api.DeleteTypePolicyHandler(c))
+	s.Equal(http.StatusNoContent, c.Response().Status)
+
+	c = deletePolicy(models.Semanal)
+	s.Error(api.DeleteTypePolicyHandler(c))
+	s.Equal(http.StatusNotFound, c.Response().Status, "default policies can not be deleted")
+
+	policies, err := managers.NewTypePolicyManager(*s.db).GetTypePolicies(userID)
+	s.NoError(err)
+	s.Equal(models.DefaultTypePolicies()[1].MinSpacing, policies[1].MinSpacing)
+}
+
+func (s *CalendarAPITestSuite) TestPostCalendarHandlerWithTypePolicy() {
+	userID := "01FN3EEB2NVFJAHAPU00000007"
+	s.NoError(repositories.NewSQLiteTypePolicyRepository(s.db).SaveTypePolicy(models.TypePolicy{
+		UserId:   userID,
+		Type:     "batch-cook",
+		Weekdays: models.IntList{0},
+		MaxCount: 1,
+		Window:   7,
+	}))
+	var meals []*models.MealToFront
+	for i := 0; i < 6; i++ {
+		mealType := models.Normal
+		if i%2 == 0 {
+			mealType = "batch-cook"
+		}
+		meals = append(meals, &models.MealToFront{
+			Id:     fmt.Sprintf("01FN3EEB2NVFJAHAPM0000040%d", i),
+			UserId: userID,
+			Name:   fmt.Sprintf("meal%d", i),
+			Type:   mealType,
+		})
+	}
+	s.httpMock.On("GetAllMeals", userID, mock.Anything).Return(meals, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, internal.RouteCalendar, nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames(internal.ParamUserID)
+	c.SetParamValues(userID)
+
+	api := CalendarAPI{DB: *s.db, Manager: managers.NewCalendarManager(*s.db)}
+	s.NoError(api.PostCalendarHandler(c))
+	s.Equal(http.StatusCreated, c.Response().Status)
+
+	calendar, err := repositories.NewSQLiteCalendarRepository(s.db).GetCalendar(userID)
+	s.NoError(err)
+	var batchDays []time.Time
+	for _, day := range calendar {
+		for _, m := range meals {
+			if m.Id == day.MealId && m.Type == "batch-cook" {
+				date, _ := time.Parse("2006/01/02", day.Date)
+				batchDays = append(batchDays, date)
+			}
+		}
+	}
+	s.NotEmpty(batchDays)
+	for i := 1; i < len(batchDays); i++ {
+		s.GreaterOrEqual(batchDays[i].Sub(batchDays[i-1]).Hours()/24, 7.0)
+	}
+}
+
+// TestDefaultTypePoliciesScoreAsBaseline pins the scores of the meal types of
+// the users that have not changed their policies to the ones of the fixed rules
+// they replace.
+func (s *CalendarAPITestSuite) TestDefaultTypePoliciesScoreAsBaseline() {
+	weekly := &models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002701", Type: models.Semanal}
+	occasional := &models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002702", Type: models.Ocasional}
+	normal := &models.MealToFront{Id: "01FN3EEB2NVFJAHAPM00002703", Type: models.Normal}
+	mealsById := map[string]*models.MealToFront{weekly.Id: weekly, occasional.Id: occasional, normal.Id: normal}
+	saturday := time.Date(2030, 1, 5, 0, 0, 0, 0, time.UTC)
+	plannedAt := func(mealId string, days ...int) (calendar []models.Calendar) {
+		for _, d := range days {
+			calendar = append(calendar, models.Calendar{MealId: mealId, Date: saturday.AddDate(0, 0, d).Format("2006/01/02")})
+		}
+		return
+	}
+	// spaced is the bonus of the weekly meals planned distance days away, computed
+	// at run time as the fixed rules did.
+	spaced := func(distance float64) float64 { return 1.6 - (1/(distance))*2.3 }
+
+	for _, tools := range []*utils.CalendarTools{
+		utils.NewCalendarToolsManager(),
+		utils.NewCalendarToolsManager().WithPreferences(utils.Preferences{
+			Settings:     models.NewUserSettings("01FN3EEB2NVFJAHAPU00000027"),
+			TypePolicies: models.DefaultTypePolicies(),
+		}),
+	} {
+		score := func(calendar []models.Calendar, meal *models.MealToFront, date time.Time) float64 {
+			return tools.TypeScore(calendar, meal, date, mealsById)
+		}
+		s.Equal(1.2, score(nil, weekly, saturday), "weekly meal never planned")
+		s.Equal(1.2, score(plannedAt(weekly.Id, 0), weekly, saturday), "weekly meal planned the same day")
+		for _, d := range []int{-1, -3, -6, 2, 6} {
+			s.Equal(0.0, score(plannedAt(weekly.Id, d), weekly, saturday), "weekly meal planned %d days away", d)
+		}
+		s.Equal(spaced(7), score(plannedAt(weekly.Id, -7), weekly, saturday))
+		s.Equal(spaced(10), score(plannedAt(weekly.Id, -14, 10), weekly, saturday))
+		s.Equal(0.0, score(plannedAt(occasional.Id, -7), occasional, saturday))
+		s.Equal(0.0, score(nil, normal, saturday))
+
+		for d, want := range map[int]float64{0: 1 + 2.10, 1: 1 + 2.10, 4: 1 - 2.9} {
+			s.Equal(want, tools.SpecialMeal(occasional, 1, saturday.AddDate(0, 0, d), 2), "occasional meal %d days after saturday", d)
+			s.Equal(1.0, tools.SpecialMeal(weekly, 1, saturday.AddDate(0, 0, d), 2))
+			s.Equal(1.0, tools.SpecialMeal(normal, 1, saturday.AddDate(0, 0, d), 2))
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/internal/utils"
+	"calendar/pkg/caldav"
+	"calendar/pkg/database"
+	"calendar/pkg/ical"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const mimeCalendarEvent = "text/calendar; charset=utf-8; component=vevent"
+
+type ICalDAVManager interface {
+	Propfind(userId, name, depth string, request caldav.Request) (responses []caldav.Response, err error)
+	Report(userId string, request caldav.Request) (responses []caldav.Response, err error)
+	GetEvent(userId, name string) (event models.CalDAVEvent, err error)
+	PutEvent(userId, name string, ics io.Reader, ifMatch, ifNoneMatch string) (event models.CalDAVEvent, err error)
+}
+
+// CalDAVManager serves the calendar of each user as a CalDAV collection with an
+// event per day with a meal. Changing the meal of an event changes the meal of
+// the day.
+type CalDAVManager struct {
+	db       *repositories.SQLiteCalendarRepository
+	calendar *CalendarManager
+}
+
+func NewCalDAVManager(db database.Database) *CalDAVManager {
+	return &CalDAVManager{
+		db:       repositories.NewSQLiteCalendarRepository(&db),
+		calendar: NewCalendarManager(db),
+	}
+}
+
+// Propfind returns the properties of the collection, and of its events with
+// depth 1 or infinity, or of the event with the name when given.
+func (m *CalDAVManager) Propfind(userId, name, depth string, request caldav.Request) (responses []caldav.Response, err error) {
+	if request.Kind != caldav.Propfind {
+		return nil, internal.ErrWrongBody
+	}
+	if name != "" {
+		event, errEvent := m.GetEvent(userId, name)
+		if errEvent != nil {
+			return nil, errEvent
+		}
+		return []caldav.Response{eventResponse(userId, event, request)}, nil
+	}
+	events, err := m.events(userId)
+	if err != nil {
+		return
+	}
+	responses = append(responses, collectionResponse(userId, events, request))
+	if depth != "0" {
+		for _, event := range events {
+			responses = append(responses, eventResponse(userId, event, request))
+		}
+	}
+	return
+}
+
+// Report answers the calendar-query, with the events in its time-range, and the
+// calendar-multiget, with the events of its hrefs.
+func (m *CalDAVManager) Report(userId string, request caldav.Request) (responses []caldav.Response, err error) {
+	events, err := m.events(userId)
+	if err != nil {
+		return
+	}
+	switch request.Kind {
+	case caldav.CalendarQuery:
+		for _, event := range events {
+			start, _ := time.Parse("2006/01/02", event.Date)
+			if (request.End.IsZero() || start.Before(request.End)) && (request.Start.IsZero() || start.AddDate(0, 0, 1).After(request.Start)) {
+				responses = append(responses, eventResponse(userId, event, request))
+			}
+		}
+	case caldav.CalendarMultiget:
+		byHref := make(map[string]models.CalDAVEvent, len(events))
+		for _, event := range events {
+			byHref[calDAVHref(userId, event.Name)] = event
+		}
+		for _, href := range request.Hrefs {
+			if event, ok := byHref[href]; ok {
+				responses = append(responses, eventResponse(userId, event, request))
+			} else {
+				responses = append(responses, caldav.Response{Href: href, Status: 404})
+			}
+		}
+	default:
+		return nil, internal.ErrWrongBody
+	}
+	return
+}
+
+// GetEvent returns the event of the day of the name, when the day has a meal.
+func (m *CalDAVManager) GetEvent(userId, name string) (event models.CalDAVEvent, err error) {
+	date, err := eventDate(name)
+	if err != nil {
+		return
+	}
+	day, err := m.db.GetCalendarSpecificDate(userId, date)
+	if errors.Is(err, internal.ErrDateNotFound) || (err == nil && day[0].MealId == "") {
+		return models.CalDAVEvent{}, internal.ErrEventNotFound
+	}
+	if err != nil {
+		return
+	}
+	return calDAVEvent(userId, day[0])
+}
+
+// PutEvent changes the meal of the day of the event to the meal named as its
+// summary. The If-Match and If-None-Match conditions are checked against the
+// ETag of the current event first. Nothing changes when the summary is still the
+// meal of the day, so the leftovers days keep their link.
+func (m *CalDAVManager) PutEvent(userId, name string, ics io.Reader, ifMatch, ifNoneMatch string) (event models.CalDAVEvent, err error) {
+	date, err := eventDate(name)
+	if err != nil {
+		return
+	}
+	day, err := m.db.GetCalendarSpecificDate(userId, date)
+	if err != nil {
+		return
+	}
+	var current models.CalDAVEvent
+	if day[0].MealId != "" {
+		if current, err = calDAVEvent(userId, day[0]); err != nil {
+			return
+		}
+	}
+	if (ifNoneMatch == "*" && current.ETag != "") || (ifMatch != "" && ifMatch != "*" && ifMatch != `"`+current.ETag+`"`) ||
+		(ifMatch == "*" && current.ETag == "") {
+		return models.CalDAVEvent{}, internal.ErrEventChanged
+	}
+
+	cal, err := ical.Parse(ics)
+	if err != nil || len(cal.Events()) == 0 {
+		return models.CalDAVEvent{}, internal.ErrInvalidICS
+	}
+	vevent := cal.Events()[0]
+	start, _ := vevent.Get("DTSTART")
+	if startDate, errDate := start.Date(); errDate != nil || startDate.Format("2006/01/02") != date {
+		return models.CalDAVEvent{}, internal.ErrInvalidICS
+	}
+	summary, _ := utils.EventSummary(vevent)
+	if same, fuzzy := utils.MatchMeal(summary, []*models.MealToFront{{Id: day[0].MealId, Name: day[0].Name}}); current.ETag != "" && same != nil && !fuzzy {
+		return current, nil
+	}
+	when, _ := time.Parse("2006/01/02", date)
+	meals, err := m.calendar.availableMeals(userId, when, when)
+	if err != nil {
+		return
+	}
+	meal, _ := utils.MatchMeal(summary, meals)
+	if meal == nil {
+		return models.CalDAVEvent{}, internal.ErrEventMealNotFound
+	}
+	if _, err = m.calendar.UpdateCalendar(userId, models.Calendar{Date: date, MealId: meal.Id}); err != nil {
+		return
+	}
+	return m.GetEvent(userId, name)
+}
+
+// events returns the events of the days of the calendar with a meal.
+func (m *CalDAVManager) events(userId string) (events []models.CalDAVEvent, err error) {
+	calendar, err := m.calendar.GetCalendar(userId)
+	if err != nil {
+		return
+	}
+	for _, day := range calendar {
+		if day.MealId == "" {
+			continue
+		}
+		event, errEvent := calDAVEvent(userId, day)
+		if errEvent != nil {
+			return nil, errEvent
+		}
+		events = append(events, event)
+	}
+	return
+}
+
+// calDAVEvent renders the day as a calendar object resource. It is stamped at
+// the day itself, so it only changes, and so its ETag, when the day does.
+// Calendar object resources cannot have a METHOD.
+func calDAVEvent(userId string, day models.Calendar) (event models.CalDAVEvent, err error) {
+	stamp, _ := time.Parse("2006/01/02", day.Date)
+	cal := utils.CalendarICS(userId, []models.Calendar{day}, nil, models.ICSOptions{}, stamp)
+	properties := cal.Properties[:0]
+	for _, p := range cal.Properties {
+		if p.Name != "METHOD" {
+			properties = append(properties, p)
+		}
+	}
+	cal.Properties = properties
+	data, err := encodeICS(cal)
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(data)
+	return models.CalDAVEvent{
+		Name: strings.ReplaceAll(day.Date, "/", "") + ".ics",
+		Date: day.Date,
+		ETag: hex.EncodeToString(sum[:16]),
+		Data: data,
+	}, nil
+}
+
+// eventDate returns the date of the day of the event name, yyyymmdd.ics.
+func eventDate(name string) (date string, err error) {
+	day, err := time.Parse("20060102", strings.TrimSuffix(name, ".ics"))
+	if err != nil || !strings.HasSuffix(name, ".ics") {
+		return "", internal.ErrEventNotFound
+	}
+	return day.Format("2006/01/02"), nil
+}
+
+func calDAVHref(userId, name string) string {
+	return "/caldav/" + userId + "/" + name
+}
+
+func collectionResponse(userId string, events []models.CalDAVEvent, request caldav.Request) caldav.Response {
+	ctag := sha256.New()
+	for _, event := range events {
+		ctag.Write([]byte(event.Name + event.ETag))
+	}
+	href := calDAVHref(userId, "")
+	return propsResponse(href, request, []caldav.Prop{
+		{Name: caldav.Name("resourcetype"), Children: []xml.StartElement{{Name: caldav.Name("collection")}, {Name: caldav.CalName("calendar")}}},
+		{Name: caldav.Name("displayname"), Text: "Comidas"},
+		{Name: caldav.Name("current-user-principal"), Href: href},
+		{Name: caldav.CalName("calendar-home-set"), Href: href},
+		{Name: caldav.CalName("supported-calendar-component-set"), Children: []xml.StartElement{
+			{Name: caldav.CalName("comp"), Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: "VEVENT"}}},
+		}},
+		{Name: xml.Name{Space: caldav.NamespaceCalendarServer, Local: "getctag"}, Text: hex.EncodeToString(ctag.Sum(nil)[:16])},
+	})
+}
+
+func eventResponse(userId string, event models.CalDAVEvent, request caldav.Request) caldav.Response {
+	props := []caldav.Prop{
+		{Name: caldav.Name("resourcetype")},
+		{Name: caldav.Name("getetag"), Text: `"` + event.ETag + `"`},
+		{Name: caldav.Name("getcontenttype"), Text: mimeCalendarEvent},
+	}
+	// The data is only sent when asked for, never with allprop.
+	if !request.AllProp && request.Wants(caldav.CalName("calendar-data")) {
+		props = append(props, caldav.Prop{Name: caldav.CalName("calendar-data"), Text: string(event.Data)})
+	}
+	return propsResponse(calDAVHref(userId, event.Name), request, props)
+}
+
+// propsResponse returns the response with the properties asked for, and the
+// ones asked for the resource does not have as missing.
+func propsResponse(href string, request caldav.Request, props []caldav.Prop) caldav.Response {
+	response := caldav.Response{Href: href}
+	found := make(map[xml.Name]bool, len(props))
+	for _, p := range props {
+		found[p.Name] = true
+		if request.Wants(p.Name) {
+			response.Props = append(response.Props, p)
+		}
+	}
+	for _, name := range request.Props {
+		if !found[name] {
+			response.Missing = append(response.Missing, name)
+		}
+	}
+	return response
+}
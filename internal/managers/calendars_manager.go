@@ -6,33 +6,67 @@ import (
 	"calendar/internal/repositories"
 	"calendar/internal/utils"
 	"calendar/pkg/database"
+	"calendar/pkg/holidays"
+	"calendar/pkg/ical"
+	"calendar/pkg/rrule"
+	"errors"
 	"github.com/go-playground/validator/v10"
+	"io"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 type ICalendarManager interface {
 	GetCalendar(id string) (calendar []models.Calendar, err error)
 	UpdateCalendar(id string, calendar models.Calendar) (calendarResponse []models.Calendar, err error)
+	PatchCalendar(id string, days []models.Calendar) (calendar []models.Calendar, err error)
+	CopyWeekCalendar(id string, weeks models.CopyWeekCalendar) (calendar []models.Calendar, warnings []models.RepeatWarning, err error)
 	UpdateDaysCalendar(id string, dates models.UpdateWeekCalendar) (calendar []models.Calendar, err error)
 	CreateCalendar(id string) (calendar []models.Calendar, err error)
 	DeleteCalendar(id string) (err error)
+	RedoCalendar(id string) (calendar []models.Calendar, err error)
 	GetFrontCalendar(calendar []models.Calendar) (finalCal []models.Calendar, err error)
+	GetCalendarSummary(id string, calendar []models.Calendar) (weeks []models.WeekSummary, err error)
+	ImportCalendar(id string, ics io.Reader, mode string) (report models.CalendarImport, err error)
+	GetCalendarCSV(id string) (data []byte, err error)
+	ImportCalendarCSV(id string, data io.Reader) (report models.CalendarCSVImport, err error)
 }
 
 var Microservices utils.EndpointsI = &utils.Endpoints{}
 
 type CalendarManager struct {
-	db       *repositories.SQLiteCalendarRepository
-	validate *validator.Validate
-	utils    *utils.CalendarTools
+	db         *repositories.SQLiteCalendarRepository
+	rules      *repositories.SQLiteRuleRepository
+	themes     *repositories.SQLiteThemeRepository
+	settings   *repositories.SQLiteSettingsRepository
+	exclusions *repositories.SQLiteExclusionRepository
+	special    *repositories.SQLiteSpecialDateRepository
+	policies   *repositories.SQLiteTypePolicyRepository
+	yields     *repositories.SQLiteMealYieldRepository
+	pantry     *repositories.SQLitePantryRepository
+	costs      *repositories.SQLiteMealCostRepository
+	prices     *repositories.SQLiteIngredientPriceRepository
+	validate   *validator.Validate
+	utils      *utils.CalendarTools
 }
 
 func NewCalendarManager(db database.Database) *CalendarManager {
 	return &CalendarManager{
-		db:       repositories.NewSQLiteCalendarRepository(&db),
-		validate: validator.New(),
-		utils:    utils.NewCalendarToolsManager(),
+		db:         repositories.NewSQLiteCalendarRepository(&db),
+		rules:      repositories.NewSQLiteRuleRepository(&db),
+		themes:     repositories.NewSQLiteThemeRepository(&db),
+		settings:   repositories.NewSQLiteSettingsRepository(&db),
+		exclusions: repositories.NewSQLiteExclusionRepository(&db),
+		special:    repositories.NewSQLiteSpecialDateRepository(&db),
+		policies:   repositories.NewSQLiteTypePolicyRepository(&db),
+		yields:     repositories.NewSQLiteMealYieldRepository(&db),
+		pantry:     repositories.NewSQLitePantryRepository(&db),
+		costs:      repositories.NewSQLiteMealCostRepository(&db),
+		prices:     repositories.NewSQLiteIngredientPriceRepository(&db),
+		validate:   validator.New(),
+		utils:      utils.NewCalendarToolsManager(),
 	}
 }
 
@@ -42,18 +76,10 @@ func (c *CalendarManager) GetCalendar(id string) (calendar []models.Calendar, er
 		return
 	}
 
-	t := time.Now()
-	wd := t.Weekday()
-	var differenceDays int
-	if wd == 0 {
-		differenceDays = 21
-	} else {
-		differenceDays = 21 + (7 - int(wd))
-	}
-	t = t.AddDate(0, 0, differenceDays)
+	_, t := calendarWindow(time.Now())
 	tFormat := t.Format("2006/01/02")
 	if !strings.EqualFold(tFormat, calendar[len(calendar)-1].Date) {
-		meals, errM := Microservices.GetAllMeals(id)
+		meals, errM := c.availableMeals(id, time.Now(), t)
 		if errM != nil {
 			return calendar, errM
 		}
@@ -68,11 +94,15 @@ func (c *CalendarManager) GetCalendar(id string) (calendar []models.Calendar, er
 			return calendar, errF
 		}
 		days := int(t.Sub(lastD).Hours() / 24)
+		tools, errT := c.calendarTools(id, meals)
+		if errT != nil {
+			return calendar, errT
+		}
 		if days > 28 {
-			calendar, err = c.utils.CalendarCreator(id, meals)
+			calendar, err = tools.CalendarCreator(id, meals)
 		}
 		//_ = s.Repository.DeleteCalendar(id)
-		calendar, err = c.utils.UpdateNewDays(id, calendar, meals, days)
+		calendar, err = tools.UpdateNewDays(id, calendar, meals, days)
 		if len(calendar) > 28 {
 			calendar = calendar[len(calendar)-28:]
 		}
@@ -87,38 +117,368 @@ func (c *CalendarManager) GetCalendar(id string) (calendar []models.Calendar, er
 	return
 }
 
+// calendarWindow returns the first and the last day of the 28 days the calendar
+// is made of, the last one being the sunday three weeks after the current week.
+func calendarWindow(now time.Time) (from, to time.Time) {
+	var differenceDays int
+	if wd := now.Weekday(); wd == 0 {
+		differenceDays = 21
+	} else {
+		differenceDays = 21 + (7 - int(wd))
+	}
+	to = now.AddDate(0, 0, differenceDays)
+	return to.AddDate(0, 0, -27), to
+}
+
 func (c *CalendarManager) UpdateCalendar(id string, calendar models.Calendar) (calendarResponse []models.Calendar, err error) {
 	var meal models.MealToFront
 	_, err = time.Parse("2006/01/02", calendar.Date)
 	if err != nil {
 		return []models.Calendar{}, internal.ErrInvalidDateFormat
 	}
-	if _, err = c.db.GetCalendarSpecificDate(id, calendar.Date); err != nil {
+	if err = c.validate.Struct(calendar); err != nil {
+		return []models.Calendar{}, internal.ErrWrongBody
+	}
+	day, err := c.db.GetCalendarSpecificDate(id, calendar.Date)
+	if err != nil {
+		return
+	}
+	if calendar.Servings == 0 {
+		calendar.Servings = day[0].Servings
+	}
+	current, err := c.db.GetCalendar(id)
+	if err != nil {
 		return
 	}
 
 	if meal, err = Microservices.GetMeal(id, calendar.MealId); err != nil {
 		return
 	}
+	exclusions, err := c.exclusions.GetExclusions(id)
+	if err != nil {
+		return []models.Calendar{}, internal.ErrSomethingWentWrong
+	}
+	if _, excluded := utils.ExcludedIngredient(&meal, exclusions, calendar.Date); excluded {
+		return []models.Calendar{}, internal.ErrExcludedIngredient
+	}
+	calendar.UserId = id
 	calendar.Name = meal.Name
+	calendar.Kcal = meal.Kcal
+
+	if err = c.swapDays(id, current, []models.Calendar{calendar}); err != nil {
+		return
+	}
+
+	return c.db.GetCalendar(id)
+}
 
-	if err = c.db.UpdateCalendar(id, calendar); err != nil {
+// PatchCalendar changes the meal of several days at once. Every date and meal is
+// validated before anything is written, and all the days are updated in one
+// transaction along with their leftovers. Invalid days are reported in an
+// *internal.ItemsError.
+func (c *CalendarManager) PatchCalendar(id string, days []models.Calendar) (calendar []models.Calendar, err error) {
+	if len(days) == 0 {
+		return []models.Calendar{}, internal.ErrWrongBody
+	}
+	if calendar, err = c.db.GetCalendar(id); err != nil {
 		return
 	}
+	calendarDates := make(map[string]models.Calendar, len(calendar))
+	for _, day := range calendar {
+		calendarDates[day.Date] = day
+	}
+
+	var itemErrors []internal.ItemError
+	requested := make(map[string]bool, len(days))
+	for i, day := range days {
+		itemErr := internal.ItemError{Index: i, Date: day.Date, MealId: day.MealId}
+		if _, errDate := time.Parse("2006/01/02", day.Date); errDate != nil {
+			itemErr.Message = internal.ErrInvalidDateFormat.Error()
+		} else if requested[day.Date] {
+			itemErr.Message = internal.ErrDuplicatedDate.Error()
+		} else if current, ok := calendarDates[day.Date]; !ok {
+			itemErr.Message = internal.ErrDateNotFound.Error()
+		} else if c.validate.Struct(day) != nil {
+			itemErr.Message = internal.ErrWrongBody.Error()
+		} else if day.Servings == 0 {
+			days[i].Servings = current.Servings
+		}
+		requested[day.Date] = true
+		if itemErr.Message != "" {
+			itemErrors = append(itemErrors, itemErr)
+		}
+	}
 
+	exclusions, err := c.exclusions.GetExclusions(id)
+	if err != nil {
+		return calendar, internal.ErrSomethingWentWrong
+	}
+	meals, mealErrors := getMeals(id, days)
+	for i, day := range days {
+		if errMeal, ok := mealErrors[day.MealId]; ok {
+			itemErrors = append(itemErrors, internal.ItemError{Index: i, Date: day.Date, MealId: day.MealId, Message: errMeal.Error()})
+			continue
+		}
+		meal := meals[day.MealId]
+		if _, excluded := utils.ExcludedIngredient(&meal, exclusions, day.Date); excluded {
+			itemErrors = append(itemErrors, internal.ItemError{Index: i, Date: day.Date, MealId: day.MealId, Message: internal.ErrExcludedIngredient.Error()})
+			continue
+		}
+		days[i].UserId = id
+		days[i].Name = meals[day.MealId].Name
+		days[i].Kcal = meals[day.MealId].Kcal
+	}
+	if len(itemErrors) > 0 {
+		sort.SliceStable(itemErrors, func(i, j int) bool { return itemErrors[i].Index < itemErrors[j].Index })
+		return calendar, &internal.ItemsError{Err: internal.ErrInvalidCalendarDays, Details: itemErrors}
+	}
+
+	if err = c.swapDays(id, calendar, days); err != nil {
+		return calendar, err
+	}
 	return c.db.GetCalendar(id)
 }
 
+// swapDays stores the given days of the calendar. The leftovers of the new meals
+// are planned on the following days, and the ones of the replaced meals are
+// planned again.
+func (c *CalendarManager) swapDays(id string, calendar, days []models.Calendar) (err error) {
+	yields, err := mealYields(c.yields, id)
+	if err != nil {
+		return
+	}
+	tools := c.utils.WithPreferences(utils.Preferences{Yields: yields})
+	calendar, orphans := tools.LinkLeftovers(calendar, days)
+	if len(orphans) > 0 {
+		from, _ := time.Parse("2006/01/02", orphans[0])
+		to, _ := time.Parse("2006/01/02", orphans[len(orphans)-1])
+		meals, errM := c.availableMeals(id, from, to)
+		if errM != nil {
+			return errM
+		}
+		if tools, err = c.calendarTools(id, meals); err != nil {
+			return
+		}
+		calendar = tools.RefillDays(id, calendar, meals, orphans)
+	}
+	if err = c.db.UpdateCalendarDays(id, calendar); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// calendarTools returns the tools that generate the calendar of the user, loaded
+// with the user's preferences.
+func (c *CalendarManager) calendarTools(id string, meals []*models.MealToFront) (tools *utils.CalendarTools, err error) {
+	var preferences utils.Preferences
+	rules, err := c.rules.GetRules(id)
+	if err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	for _, r := range rules {
+		start, errStart := time.Parse("2006/01/02", r.Start)
+		if errStart != nil {
+			continue
+		}
+		rule, errRule := rrule.Parse(r.Rule, start)
+		if errRule != nil {
+			continue
+		}
+		meal, ok := resolveMeal(id, r.MealId, meals)
+		if !ok {
+			continue
+		}
+		preferences.Rules = append(preferences.Rules, utils.MealRule{Rule: rule, Meal: meal})
+	}
+	if preferences.Themes, err = c.themes.GetThemes(id); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	if preferences.Settings, err = c.settings.GetSettings(id); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	if preferences.Exclusions, err = c.exclusions.GetExclusions(id); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	if preferences.Holidays, err = c.holidays(preferences.Settings); err != nil {
+		return nil, err
+	}
+	if preferences.TypePolicies, err = typePolicies(c.policies, id); err != nil {
+		return nil, err
+	}
+	if preferences.Yields, err = mealYields(c.yields, id); err != nil {
+		return nil, err
+	}
+	if preferences.Pantry, err = c.pantry.GetPantryItems(id); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	if preferences.Costs, err = mealCosts(c.costs, id); err != nil {
+		return nil, err
+	}
+	if preferences.Prices, err = c.prices.GetIngredientPrices(id); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	return c.utils.WithPreferences(preferences), nil
+}
+
+// holidays returns the public holidays of the user's country and region plus
+// the user's special dates. Unknown countries add no holidays, as they are
+// validated when the settings are saved.
+func (c *CalendarManager) holidays(settings models.UserSettings) (list holidays.List, err error) {
+	if settings.HolidayCountry != "" {
+		list, _ = holidays.Load(settings.HolidayCountry, settings.HolidayRegion)
+	}
+	dates, err := c.special.GetSpecialDates(settings.UserId)
+	if err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	for _, d := range dates {
+		list = append(list, holidays.Holiday{Date: d.Date, Name: d.Name})
+	}
+	return list, nil
+}
+
+// availableMeals returns the meals of the user for every season between the from
+// and to dates, without the ones containing a permanently excluded ingredient.
+// Seasons and temporary exclusions are applied per day by the calendar tools.
+func (c *CalendarManager) availableMeals(id string, from, to time.Time) (meals []*models.MealToFront, err error) {
+	userSettings, err := c.settings.GetSettings(id)
+	if err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	if meals, err = Microservices.GetAllMeals(id, utils.SeasonsBetween(from, to, userSettings.Hemisphere)); err != nil {
+		return
+	}
+	userExclusions, err := c.exclusions.GetExclusions(id)
+	if err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	return utils.FilterExcludedMeals(meals, userExclusions), nil
+}
+
+// resolveMeal looks the meal up in the user's meals first and in the meals
+// service after that, as the meals fetched for generation are season filtered.
+func resolveMeal(userId, mealId string, meals []*models.MealToFront) (meal models.MealToFront, ok bool) {
+	if mealId == "" {
+		return
+	}
+	for _, m := range meals {
+		if m.Id == mealId {
+			return *m, true
+		}
+	}
+	meal, err := Microservices.GetMeal(userId, mealId)
+	if err != nil {
+		return models.MealToFront{}, false
+	}
+	meal.Id = mealId
+	return meal, true
+}
+
+// getMeals resolves in parallel every distinct meal of the given days.
+func getMeals(id string, days []models.Calendar) (meals map[string]models.MealToFront, errs map[string]error) {
+	meals = map[string]models.MealToFront{}
+	errs = map[string]error{}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	seen := map[string]bool{}
+	for _, day := range days {
+		if seen[day.MealId] {
+			continue
+		}
+		seen[day.MealId] = true
+		if day.MealId == "" {
+			mu.Lock()
+			errs[day.MealId] = internal.ErrMealNotFound
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(mealId string) {
+			defer wg.Done()
+			meal, err := Microservices.GetMeal(id, mealId)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[mealId] = err
+				return
+			}
+			meals[mealId] = meal
+		}(day.MealId)
+	}
+	wg.Wait()
+	return
+}
+
+// CopyWeekCalendar copies the seven days starting at weeks.From onto the seven
+// days starting at weeks.To. Days that fall outside the calendar are skipped.
+// Leftovers stay linked when their meal is copied too, and the days left out of
+// the week lose the link to the meals overwritten.
+func (c *CalendarManager) CopyWeekCalendar(id string, weeks models.CopyWeekCalendar) (calendar []models.Calendar, warnings []models.RepeatWarning, err error) {
+	from, err := time.Parse("2006/01/02", weeks.From)
+	if err != nil {
+		return []models.Calendar{}, nil, internal.ErrInvalidDateFormat
+	}
+	to, err := time.Parse("2006/01/02", weeks.To)
+	if err != nil {
+		return []models.Calendar{}, nil, internal.ErrInvalidDateFormat
+	}
+	if calendar, err = c.db.GetCalendar(id); err != nil {
+		return
+	}
+	days := make(map[string]models.Calendar, len(calendar))
+	for _, day := range calendar {
+		days[day.Date] = day
+	}
+	if _, ok := days[weeks.From]; !ok {
+		return calendar, nil, internal.ErrDateNotFound
+	}
+	if _, ok := days[weeks.To]; !ok {
+		return calendar, nil, internal.ErrDateNotFound
+	}
+
+	var copied []models.Calendar
+	var dates []string
+	for i := 0; i < 7; i++ {
+		source, okSource := days[from.AddDate(0, 0, i).Format("2006/01/02")]
+		targetDate := to.AddDate(0, 0, i).Format("2006/01/02")
+		target, okTarget := days[targetDate]
+		if !okSource || !okTarget {
+			continue
+		}
+		day := models.Calendar{UserId: id, MealId: source.MealId, Name: source.Name, Kcal: source.Kcal, Date: targetDate, Servings: target.Servings}
+		if cooked, errL := time.Parse("2006/01/02", source.LeftoverOf); errL == nil {
+			if shift := cooked.Sub(from).Hours() / 24; shift >= 0 && shift < 7 {
+				day.LeftoverOf = to.AddDate(0, 0, int(shift)).Format("2006/01/02")
+			}
+		}
+		copied = append(copied, day)
+		dates = append(dates, targetDate)
+	}
+	copied = append(copied, c.utils.UnlinkLeftovers(calendar, dates)...)
+	if err = c.db.UpdateCalendarDays(id, copied); err != nil {
+		return calendar, nil, internal.ErrSomethingWentWrong
+	}
+	if calendar, err = c.db.GetCalendar(id); err != nil {
+		return
+	}
+	if weeks.CheckRepeats {
+		warnings = c.utils.CheckRepeats(calendar, dates)
+	}
+	return
+}
+
 func (c *CalendarManager) UpdateDaysCalendar(id string, dates models.UpdateWeekCalendar) (calendar []models.Calendar, err error) {
 	calendar, err = c.db.GetCalendar(id)
 	if err != nil {
 		return []models.Calendar{}, err
 	}
-	_, err = time.Parse("2006/01/02", dates.From)
+	from, err := time.Parse("2006/01/02", dates.From)
 	if err != nil {
 		return []models.Calendar{}, internal.ErrInvalidDateFormat
 	}
-	_, err = time.Parse("2006/01/02", dates.To)
+	to, err := time.Parse("2006/01/02", dates.To)
 	if err != nil {
 		return []models.Calendar{}, internal.ErrInvalidDateFormat
 	}
@@ -128,7 +488,7 @@ func (c *CalendarManager) UpdateDaysCalendar(id string, dates models.UpdateWeekC
 	if _, err = c.db.GetCalendarSpecificDate(id, dates.To); err != nil {
 		return nil, err
 	}
-	meals, err := Microservices.GetAllMeals(id)
+	meals, err := c.availableMeals(id, from, to)
 	if err != nil {
 		return
 	}
@@ -138,7 +498,11 @@ func (c *CalendarManager) UpdateDaysCalendar(id string, dates models.UpdateWeekC
 		}
 		return []models.Calendar{}, internal.ErrMealsNotFound
 	}
-	finalCal, err := c.utils.UpdateDaysInCalendar(id, calendar, meals, dates)
+	tools, err := c.calendarTools(id, meals)
+	if err != nil {
+		return []models.Calendar{}, err
+	}
+	finalCal, err := tools.UpdateDaysInCalendar(id, calendar, meals, dates)
 	if err != nil {
 		return []models.Calendar{}, err
 	}
@@ -155,7 +519,7 @@ func (c *CalendarManager) CreateCalendar(id string) (calendar []models.Calendar,
 	if _, err = c.db.GetCalendar(id); err == nil {
 		return []models.Calendar{}, internal.ErrCalendarAlreadyExists
 	}
-	meals, err := Microservices.GetAllMeals(id)
+	meals, err := c.availableMeals(id, time.Now(), time.Now().AddDate(0, 0, 28))
 	if len(meals) == 0 {
 		if err = c.db.DeleteCalendar(id); err != nil {
 			return []models.Calendar{}, internal.ErrSomethingWentWrong
@@ -165,7 +529,11 @@ func (c *CalendarManager) CreateCalendar(id string) (calendar []models.Calendar,
 	if err != nil {
 		return
 	}
-	calendar, err = c.utils.CalendarCreator(id, meals)
+	tools, err := c.calendarTools(id, meals)
+	if err != nil {
+		return
+	}
+	calendar, err = tools.CalendarCreator(id, meals)
 	if err != nil {
 		return
 	}
@@ -183,17 +551,285 @@ func (c *CalendarManager) DeleteCalendar(id string) (err error) {
 	return c.db.DeleteCalendar(id)
 }
 
+// RedoCalendar generates a new calendar for the user and replaces the stored one
+// atomically. If the generation fails the current calendar is returned untouched
+// along with the error.
+func (c *CalendarManager) RedoCalendar(id string) (calendar []models.Calendar, err error) {
+	if calendar, err = c.db.GetCalendar(id); err != nil {
+		return
+	}
+	meals, err := c.availableMeals(id, time.Now(), time.Now().AddDate(0, 0, 28))
+	if err != nil {
+		return calendar, err
+	}
+	if len(meals) == 0 {
+		return calendar, internal.ErrMealsNotFound
+	}
+	tools, err := c.calendarTools(id, meals)
+	if err != nil {
+		return calendar, err
+	}
+	newCalendar, err := tools.CalendarCreator(id, meals)
+	if err != nil {
+		return calendar, err
+	}
+	if err = c.db.ReplaceCalendar(id, newCalendar); err != nil {
+		return calendar, internal.ErrSomethingWentWrong
+	}
+	return newCalendar, nil
+}
+
 func (c *CalendarManager) GetFrontCalendar(calendar []models.Calendar) (finalCal []models.Calendar, err error) {
 	diff := 28 - len(calendar)
 	firstDate, _ := time.Parse("2006/01/02", calendar[0].Date)
 	for i := 0; i < diff; i++ {
 		noMealDate := firstDate.AddDate(0, 0, -(diff - i))
-		calAux := models.Calendar{MealId: "", Name: "NO MEAL", Date: noMealDate.Format("2006/01/02")}
+		calAux := models.Calendar{MealId: "", Name: models.NoMeal, Date: noMealDate.Format("2006/01/02")}
 		finalCal = append(finalCal, calAux)
 	}
 	for _, cal := range calendar {
-		calAux := models.Calendar{MealId: cal.MealId, UserId: cal.UserId, Date: cal.Date, Name: cal.Name}
+		calAux := models.Calendar{MealId: cal.MealId, UserId: cal.UserId, Date: cal.Date, Name: cal.Name, Kcal: cal.Kcal, LeftoverOf: cal.LeftoverOf, Servings: cal.Servings}
 		finalCal = append(finalCal, calAux)
 	}
 	return
 }
+
+// GetCalendarSummary returns the weekly totals of the calendar and their
+// deviation from the user's targets. When the user has ingredient prices the
+// meals without a cost set are fetched to cost them, and the ones that cannot be
+// fetched are left out of the spend.
+func (c *CalendarManager) GetCalendarSummary(id string, calendar []models.Calendar) (weeks []models.WeekSummary, err error) {
+	preferences := utils.Preferences{}
+	if preferences.Settings, err = c.settings.GetSettings(id); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	if preferences.Costs, err = mealCosts(c.costs, id); err != nil {
+		return nil, err
+	}
+	if preferences.Prices, err = c.prices.GetIngredientPrices(id); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	mealsById := map[string]*models.MealToFront{}
+	if len(preferences.Prices) > 0 {
+		var uncosted []models.Calendar
+		for _, day := range calendar {
+			if _, ok := preferences.Costs[day.MealId]; !ok && day.MealId != "" && day.LeftoverOf == "" {
+				uncosted = append(uncosted, day)
+			}
+		}
+		meals, _ := getMeals(id, uncosted)
+		for mealId, meal := range meals {
+			meal := meal
+			meal.Id = mealId
+			mealsById[mealId] = &meal
+		}
+	}
+	tools := c.utils.WithPreferences(preferences)
+	return tools.WeekSummaries(calendar, mealsById), nil
+}
+
+// ImportCalendar imports the events of the iCalendar whose summary matches one
+// of the user's meals and whose date is one of the 28 days of the calendar. When
+// merging, the imported days replace the ones with the same date of the existing
+// calendar. When replacing, the calendar is made of the imported days and the
+// rest of the 28 days without a meal. Nothing is written when no event matches.
+func (c *CalendarManager) ImportCalendar(id string, ics io.Reader, mode string) (report models.CalendarImport, err error) {
+	if mode == "" {
+		mode = models.ImportMerge
+	}
+	if mode != models.ImportMerge && mode != models.ImportReplace {
+		return models.CalendarImport{}, internal.ErrInvalidImportMode
+	}
+	cal, err := ical.Parse(ics)
+	if err != nil {
+		return models.CalendarImport{}, internal.ErrInvalidICS
+	}
+	events := cal.Events()
+	from, to, found := time.Now(), time.Now(), false
+	for _, event := range events {
+		start, _ := event.Get("DTSTART")
+		date, errDate := start.Date()
+		if errDate != nil {
+			continue
+		}
+		if !found || date.Before(from) {
+			from = date
+		}
+		if !found || date.After(to) {
+			to = date
+		}
+		found = true
+	}
+	meals, err := c.availableMeals(id, from, to)
+	if err != nil {
+		return
+	}
+	exclusions, err := c.exclusions.GetExclusions(id)
+	if err != nil {
+		return models.CalendarImport{}, internal.ErrSomethingWentWrong
+	}
+	first, last := calendarWindow(time.Now())
+	days, report := utils.ImportEvents(id, events, meals, exclusions, first.Format("2006/01/02"), last.Format("2006/01/02"))
+	report.Mode = mode
+	if report.Calendar, err = c.writeImported(id, days, mode == models.ImportReplace); err != nil {
+		return models.CalendarImport{}, err
+	}
+	return report, nil
+}
+
+// writeImported writes the imported days, all of them in the calendar window,
+// over the days of the calendar with the same date keeping their servings, once
+// the calendar is moved to the current window. When replacing, the calendar is
+// made of the 28 days of the window, the ones not imported without a meal, and
+// it does not need to exist. It returns the calendar after, and writes nothing
+// when there are no days.
+func (c *CalendarManager) writeImported(id string, days []models.Calendar, replace bool) (calendar []models.Calendar, err error) {
+	if !replace {
+		if calendar, err = c.GetCalendar(id); err != nil {
+			return nil, err
+		}
+	} else if calendar, err = c.db.GetCalendar(id); err != nil && !errors.Is(err, internal.ErrCalendarNotFound) {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	if len(days) > 0 {
+		settings, errSettings := c.settings.GetSettings(id)
+		if errSettings != nil {
+			return nil, internal.ErrSomethingWentWrong
+		}
+		if replace {
+			err = c.db.ReplaceCalendar(id, windowDays(id, days, settings.DefaultServings))
+		} else {
+			servings := make(map[string]int, len(calendar))
+			for _, day := range calendar {
+				servings[day.Date] = day.Servings
+			}
+			dates := make([]string, len(days))
+			for i := range days {
+				days[i].Servings = settings.DefaultServings
+				if s, ok := servings[days[i].Date]; ok {
+					days[i].Servings = s
+				}
+				dates[i] = days[i].Date
+			}
+			err = c.db.MergeCalendar(append(days, c.utils.UnlinkLeftovers(calendar, dates)...))
+		}
+		if err != nil {
+			return nil, internal.ErrSomethingWentWrong
+		}
+		if calendar, err = c.db.GetCalendar(id); err != nil {
+			return
+		}
+	}
+	if calendar == nil {
+		calendar = []models.Calendar{}
+	}
+	return calendar, nil
+}
+
+// windowDays returns the 28 days of the current calendar window with the given
+// days in place and the rest of them without a meal.
+func windowDays(id string, days []models.Calendar, servings int) (window []models.Calendar) {
+	byDate := make(map[string]models.Calendar, len(days))
+	for _, day := range days {
+		byDate[day.Date] = day
+	}
+	first, _ := calendarWindow(time.Now())
+	for i := 0; i < 28; i++ {
+		date := first.AddDate(0, 0, i).Format("2006/01/02")
+		day, ok := byDate[date]
+		if !ok {
+			day = models.Calendar{UserId: id, MealId: "", Name: models.NoMeal, Date: date}
+		}
+		day.Servings = servings
+		window = append(window, day)
+	}
+	return
+}
+
+// GetCalendarCSV returns the calendar, moved to the current window as in
+// GetCalendar, as CSV. The type of the meals that cannot be fetched is left
+// empty.
+func (c *CalendarManager) GetCalendarCSV(id string) (data []byte, err error) {
+	calendar, err := c.GetCalendar(id)
+	if err != nil {
+		return
+	}
+	var days []models.Calendar
+	for _, day := range calendar {
+		if day.MealId != "" {
+			days = append(days, day)
+		}
+	}
+	meals, _ := getMeals(id, days)
+	if data, err = utils.CalendarCSV(calendar, meals); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// ImportCalendarCSV writes the valid rows of the CSV over the days of the
+// existing calendar with the same date, in one transaction, and reports the
+// rest. A row is valid when its date has the aaaa/MM/dd format, is in the
+// calendar window, is not repeated and its meal exists and has no excluded
+// ingredient. Rows without a meal are skipped, leaving the day as it is.
+func (c *CalendarManager) ImportCalendarCSV(id string, data io.Reader) (report models.CalendarCSVImport, err error) {
+	rows, err := utils.ParseCalendarCSV(data)
+	if err != nil {
+		return models.CalendarCSVImport{}, internal.ErrInvalidCSV
+	}
+	exclusions, err := c.exclusions.GetExclusions(id)
+	if err != nil {
+		return models.CalendarCSVImport{}, internal.ErrSomethingWentWrong
+	}
+	first, last := calendarWindow(time.Now())
+	from, to := first.Format("2006/01/02"), last.Format("2006/01/02")
+	report.Errors = []models.CSVRowError{}
+	var days []models.Calendar
+	requested := make(map[string]int, len(rows))
+	for _, row := range rows {
+		if row.MealId == "" {
+			continue
+		}
+		rowErr := models.CSVRowError{Row: row.Row, Date: row.Date, MealId: row.MealId}
+		if _, errDate := time.Parse("2006/01/02", row.Date); errDate != nil {
+			rowErr.Message = internal.ErrInvalidDateFormat.Error()
+		} else if row.Date < from || row.Date > to {
+			rowErr.Message = internal.ErrDateNotFound.Error()
+		} else if _, ok := requested[row.Date]; ok {
+			rowErr.Message = internal.ErrDuplicatedDate.Error()
+		} else {
+			requested[row.Date] = row.Row
+		}
+		if rowErr.Message != "" {
+			report.Errors = append(report.Errors, rowErr)
+			continue
+		}
+		days = append(days, models.Calendar{UserId: id, MealId: row.MealId, Date: row.Date})
+	}
+
+	meals, mealErrors := getMeals(id, days)
+	valid := days[:0]
+	for i, day := range days {
+		rowErr := models.CSVRowError{Row: requested[day.Date], Date: day.Date, MealId: day.MealId}
+		meal := meals[day.MealId]
+		if errMeal, ok := mealErrors[day.MealId]; ok {
+			rowErr.Message = errMeal.Error()
+		} else if _, excluded := utils.ExcludedIngredient(&meal, exclusions, day.Date); excluded {
+			rowErr.Message = internal.ErrExcludedIngredient.Error()
+		}
+		if rowErr.Message != "" {
+			report.Errors = append(report.Errors, rowErr)
+			continue
+		}
+		days[i].Name = meal.Name
+		days[i].Kcal = meal.Kcal
+		valid = append(valid, days[i])
+	}
+	sort.SliceStable(report.Errors, func(i, j int) bool { return report.Errors[i].Row < report.Errors[j].Row })
+
+	report.Imported = len(valid)
+	if report.Calendar, err = c.writeImported(id, valid, false); err != nil {
+		return models.CalendarCSVImport{}, err
+	}
+	return report, nil
+}
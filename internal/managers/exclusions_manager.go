@@ -0,0 +1,96 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/oklog/ulid/v2"
+	"strings"
+	"time"
+)
+
+type IExclusionManager interface {
+	GetExclusions(userId string) (exclusions []models.IngredientExclusion, err error)
+	CreateExclusion(userId string, exclusion models.IngredientExclusion) (exclusionResponse models.IngredientExclusion, err error)
+	UpdateExclusion(userId, id string, exclusion models.IngredientExclusion) (exclusionResponse models.IngredientExclusion, err error)
+	DeleteExclusion(userId, id string) (err error)
+}
+
+type ExclusionManager struct {
+	db       *repositories.SQLiteExclusionRepository
+	validate *validator.Validate
+}
+
+func NewExclusionManager(db database.Database) *ExclusionManager {
+	return &ExclusionManager{
+		db:       repositories.NewSQLiteExclusionRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (e *ExclusionManager) GetExclusions(userId string) (exclusions []models.IngredientExclusion, err error) {
+	if exclusions, err = e.db.GetExclusions(userId); err != nil {
+		return []models.IngredientExclusion{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (e *ExclusionManager) CreateExclusion(userId string, exclusion models.IngredientExclusion) (exclusionResponse models.IngredientExclusion, err error) {
+	if err = e.validateExclusion(&exclusion); err != nil {
+		return
+	}
+	exclusion.Id = ulid.Make().String()
+	exclusion.UserId = userId
+	if err = e.db.CreateExclusion(exclusion); err != nil {
+		return models.IngredientExclusion{}, internal.ErrSomethingWentWrong
+	}
+	return e.db.GetExclusion(userId, exclusion.Id)
+}
+
+func (e *ExclusionManager) UpdateExclusion(userId, id string, exclusion models.IngredientExclusion) (exclusionResponse models.IngredientExclusion, err error) {
+	if _, err = e.db.GetExclusion(userId, id); err != nil {
+		return
+	}
+	if err = e.validateExclusion(&exclusion); err != nil {
+		return
+	}
+	exclusion.Id = id
+	exclusion.UserId = userId
+	if err = e.db.UpdateExclusion(exclusion); err != nil {
+		return models.IngredientExclusion{}, internal.ErrSomethingWentWrong
+	}
+	return e.db.GetExclusion(userId, id)
+}
+
+func (e *ExclusionManager) DeleteExclusion(userId, id string) (err error) {
+	if _, err = e.db.GetExclusion(userId, id); err != nil {
+		return
+	}
+	if err = e.db.DeleteExclusion(userId, id); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// validateExclusion checks the exclusion and the format of its optional dates.
+// The until date can not be before the from date.
+func (e *ExclusionManager) validateExclusion(exclusion *models.IngredientExclusion) (err error) {
+	exclusion.Ingredient = strings.TrimSpace(exclusion.Ingredient)
+	if err = e.validate.Struct(exclusion); err != nil {
+		return internal.ErrWrongBody
+	}
+	for _, date := range []string{exclusion.From, exclusion.Until} {
+		if date == "" {
+			continue
+		}
+		if _, err = time.Parse("2006/01/02", date); err != nil {
+			return internal.ErrInvalidDateFormat
+		}
+	}
+	if exclusion.From != "" && exclusion.Until != "" && exclusion.Until < exclusion.From {
+		return internal.ErrWrongBody
+	}
+	return
+}
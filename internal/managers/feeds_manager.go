@@ -0,0 +1,137 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/internal/utils"
+	"calendar/pkg/database"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// feedTokenBytes is the length of the random feed tokens.
+const feedTokenBytes = 32
+
+type IFeedManager interface {
+	CreateFeedToken(userId string) (token models.FeedToken, err error)
+	RotateFeedToken(userId string) (token models.FeedToken, err error)
+	RevokeFeedToken(userId string) (err error)
+	GetFeed(token string, options models.ICSOptions) (feed models.Feed, err error)
+}
+
+type FeedManager struct {
+	db  *repositories.SQLiteFeedRepository
+	ics *ICSManager
+}
+
+func NewFeedManager(db database.Database) *FeedManager {
+	return &FeedManager{
+		db:  repositories.NewSQLiteFeedRepository(&db),
+		ics: NewICSManager(db),
+	}
+}
+
+// CreateFeedToken creates the feed token of the user, who can only have one.
+func (f *FeedManager) CreateFeedToken(userId string) (token models.FeedToken, err error) {
+	if _, err = f.db.GetFeedToken(userId); err == nil {
+		return models.FeedToken{}, internal.ErrFeedTokenAlreadyExists
+	} else if !errors.Is(err, internal.ErrFeedTokenNotFound) {
+		return models.FeedToken{}, internal.ErrSomethingWentWrong
+	}
+	if token, err = newFeedToken(userId); err != nil {
+		return
+	}
+	if err = f.db.CreateFeedToken(token); err != nil {
+		return models.FeedToken{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// RotateFeedToken replaces the feed token of the user with a new one, so the URL
+// of the old one stops working.
+func (f *FeedManager) RotateFeedToken(userId string) (token models.FeedToken, err error) {
+	if _, err = f.db.GetFeedToken(userId); err != nil {
+		return
+	}
+	if token, err = newFeedToken(userId); err != nil {
+		return
+	}
+	if err = f.db.UpdateFeedToken(token); err != nil {
+		return models.FeedToken{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (f *FeedManager) RevokeFeedToken(userId string) (err error) {
+	if _, err = f.db.GetFeedToken(userId); err != nil {
+		return
+	}
+	if err = f.db.DeleteFeedToken(userId); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// GetFeed returns the calendar of the owner of the token in the iCalendar format,
+// with its ETag and the time its content last changed with the same options.
+// The events are stamped with that time, so the feed stays the same while the
+// calendar does. The calendar is moved to the current window when read, as the
+// clients polling the feed may be the only ones reading it.
+func (f *FeedManager) GetFeed(token string, options models.ICSOptions) (feed models.Feed, err error) {
+	stored, err := f.db.GetFeedTokenByHash(hashFeedToken(token))
+	if err != nil {
+		return
+	}
+	calendar, meals, err := f.ics.icsData(stored.UserId, options)
+	if err != nil {
+		return
+	}
+	content, err := encodeICS(utils.CalendarICS(stored.UserId, calendar, meals, options, time.Time{}))
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(content)
+	feed.ETag = hex.EncodeToString(sum[:16])
+	version, err := f.db.GetFeedVersion(stored.UserId, utils.ICSOptionsKey(options))
+	if err != nil {
+		return models.Feed{}, internal.ErrSomethingWentWrong
+	}
+	feed.Modified, err = time.Parse(time.RFC3339, version.ModifiedAt)
+	if err != nil || feed.ETag != version.ETag {
+		feed.Modified = time.Now().UTC().Truncate(time.Second)
+		version.ETag, version.ModifiedAt = feed.ETag, feed.Modified.Format(time.RFC3339)
+		if err = f.db.SaveFeedVersion(version); err != nil {
+			return models.Feed{}, internal.ErrSomethingWentWrong
+		}
+	}
+	feed.Data, err = encodeICS(utils.CalendarICS(stored.UserId, calendar, meals, options, feed.Modified))
+	return
+}
+
+// newFeedToken returns a new random token for the user, with its hash and the
+// URL of its feed.
+func newFeedToken(userId string) (token models.FeedToken, err error) {
+	secret := make([]byte, feedTokenBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return models.FeedToken{}, internal.ErrSomethingWentWrong
+	}
+	token = models.FeedToken{
+		UserId:    userId,
+		Token:     base64.RawURLEncoding.EncodeToString(secret),
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	token.Hash = hashFeedToken(token.Token)
+	token.URL = strings.Replace(internal.RouteFeed, ":"+internal.ParamFeedToken, token.Token+".ics", 1)
+	return
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,70 @@
+package managers
+
+import (
+	"bytes"
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/utils"
+	"calendar/pkg/database"
+	"calendar/pkg/ical"
+	"time"
+)
+
+type IICSManager interface {
+	ExportCalendar(userId string, options models.ICSOptions) (data []byte, err error)
+}
+
+type ICSManager struct {
+	calendar *CalendarManager
+}
+
+func NewICSManager(db database.Database) *ICSManager {
+	return &ICSManager{
+		calendar: NewCalendarManager(db),
+	}
+}
+
+// ExportCalendar returns the calendar of the user in the iCalendar format.
+func (m *ICSManager) ExportCalendar(userId string, options models.ICSOptions) (data []byte, err error) {
+	calendar, meals, err := m.icsData(userId, options)
+	if err != nil {
+		return
+	}
+	return encodeICS(utils.CalendarICS(userId, calendar, meals, options, time.Now()))
+}
+
+// icsData returns the calendar of the user, moved to the current window, and its
+// meals, which are only fetched when their ingredients are asked for. The meals
+// that cannot be fetched are exported without them.
+func (m *ICSManager) icsData(userId string, options models.ICSOptions) (calendar []models.Calendar, meals map[string]models.MealToFront, err error) {
+	if options.MealTime != "" {
+		if _, err = time.Parse("15:04", options.MealTime); err != nil {
+			return nil, nil, internal.ErrInvalidICSOptions
+		}
+	}
+	if options.Duration < 0 || options.Duration > 24*60 {
+		return nil, nil, internal.ErrInvalidICSOptions
+	}
+	if calendar, err = m.calendar.GetCalendar(userId); err != nil {
+		return
+	}
+	meals = map[string]models.MealToFront{}
+	if options.Ingredients {
+		var days []models.Calendar
+		for _, day := range calendar {
+			if day.MealId != "" {
+				days = append(days, day)
+			}
+		}
+		meals, _ = getMeals(userId, days)
+	}
+	return
+}
+
+func encodeICS(calendar *ical.Component) (data []byte, err error) {
+	var b bytes.Buffer
+	if err = ical.Encode(&b, calendar); err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/oklog/ulid/v2"
+	"strings"
+)
+
+type IIngredientPriceManager interface {
+	GetIngredientPrices(userId string) (prices []models.IngredientPrice, err error)
+	CreateIngredientPrice(userId string, price models.IngredientPrice) (priceResponse models.IngredientPrice, err error)
+	UpdateIngredientPrice(userId, id string, price models.IngredientPrice) (priceResponse models.IngredientPrice, err error)
+	DeleteIngredientPrice(userId, id string) (err error)
+}
+
+type IngredientPriceManager struct {
+	db       *repositories.SQLiteIngredientPriceRepository
+	validate *validator.Validate
+}
+
+func NewIngredientPriceManager(db database.Database) *IngredientPriceManager {
+	return &IngredientPriceManager{
+		db:       repositories.NewSQLiteIngredientPriceRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (p *IngredientPriceManager) GetIngredientPrices(userId string) (prices []models.IngredientPrice, err error) {
+	if prices, err = p.db.GetIngredientPrices(userId); err != nil {
+		return []models.IngredientPrice{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (p *IngredientPriceManager) CreateIngredientPrice(userId string, price models.IngredientPrice) (priceResponse models.IngredientPrice, err error) {
+	price.Ingredient = strings.TrimSpace(price.Ingredient)
+	if err = p.validate.Struct(price); err != nil {
+		return models.IngredientPrice{}, internal.ErrWrongBody
+	}
+	price.Id = ulid.Make().String()
+	price.UserId = userId
+	if err = p.db.CreateIngredientPrice(price); err != nil {
+		return models.IngredientPrice{}, internal.ErrSomethingWentWrong
+	}
+	return p.db.GetIngredientPrice(userId, price.Id)
+}
+
+func (p *IngredientPriceManager) UpdateIngredientPrice(userId, id string, price models.IngredientPrice) (priceResponse models.IngredientPrice, err error) {
+	if _, err = p.db.GetIngredientPrice(userId, id); err != nil {
+		return
+	}
+	price.Ingredient = strings.TrimSpace(price.Ingredient)
+	if err = p.validate.Struct(price); err != nil {
+		return models.IngredientPrice{}, internal.ErrWrongBody
+	}
+	price.Id = id
+	price.UserId = userId
+	if err = p.db.UpdateIngredientPrice(price); err != nil {
+		return models.IngredientPrice{}, internal.ErrSomethingWentWrong
+	}
+	return p.db.GetIngredientPrice(userId, id)
+}
+
+func (p *IngredientPriceManager) DeleteIngredientPrice(userId, id string) (err error) {
+	if _, err = p.db.GetIngredientPrice(userId, id); err != nil {
+		return
+	}
+	if err = p.db.DeleteIngredientPrice(userId, id); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
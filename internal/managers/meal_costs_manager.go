@@ -0,0 +1,71 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"github.com/go-playground/validator/v10"
+)
+
+type IMealCostManager interface {
+	GetMealCosts(userId string) (costs []models.MealCost, err error)
+	UpdateMealCost(userId, mealId string, cost models.MealCost) (costResponse models.MealCost, err error)
+	DeleteMealCost(userId, mealId string) (err error)
+}
+
+type MealCostManager struct {
+	db       *repositories.SQLiteMealCostRepository
+	validate *validator.Validate
+}
+
+func NewMealCostManager(db database.Database) *MealCostManager {
+	return &MealCostManager{
+		db:       repositories.NewSQLiteMealCostRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (m *MealCostManager) GetMealCosts(userId string) (costs []models.MealCost, err error) {
+	if costs, err = m.db.GetMealCosts(userId); err != nil {
+		return []models.MealCost{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (m *MealCostManager) UpdateMealCost(userId, mealId string, cost models.MealCost) (costResponse models.MealCost, err error) {
+	if err = m.validate.Struct(cost); err != nil {
+		return models.MealCost{}, internal.ErrWrongBody
+	}
+	if _, err = Microservices.GetMeal(userId, mealId); err != nil {
+		return models.MealCost{}, err
+	}
+	cost.UserId = userId
+	cost.MealId = mealId
+	if err = m.db.SaveMealCost(cost); err != nil {
+		return models.MealCost{}, internal.ErrSomethingWentWrong
+	}
+	return m.db.GetMealCost(userId, mealId)
+}
+
+func (m *MealCostManager) DeleteMealCost(userId, mealId string) (err error) {
+	if _, err = m.db.GetMealCost(userId, mealId); err != nil {
+		return
+	}
+	if err = m.db.DeleteMealCost(userId, mealId); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func mealCosts(db *repositories.SQLiteMealCostRepository, userId string) (costs map[string]float64, err error) {
+	list, err := db.GetMealCosts(userId)
+	if err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	costs = make(map[string]float64, len(list))
+	for _, c := range list {
+		costs[c.MealId] = c.Cost
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"github.com/go-playground/validator/v10"
+)
+
+type IMealYieldManager interface {
+	GetMealYields(userId string) (yields []models.MealYield, err error)
+	UpdateMealYield(userId, mealId string, yield models.MealYield) (yieldResponse models.MealYield, err error)
+	DeleteMealYield(userId, mealId string) (err error)
+}
+
+type MealYieldManager struct {
+	db       *repositories.SQLiteMealYieldRepository
+	validate *validator.Validate
+}
+
+func NewMealYieldManager(db database.Database) *MealYieldManager {
+	return &MealYieldManager{
+		db:       repositories.NewSQLiteMealYieldRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (m *MealYieldManager) GetMealYields(userId string) (yields []models.MealYield, err error) {
+	if yields, err = m.db.GetMealYields(userId); err != nil {
+		return []models.MealYield{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (m *MealYieldManager) UpdateMealYield(userId, mealId string, yield models.MealYield) (yieldResponse models.MealYield, err error) {
+	if err = m.validate.Struct(yield); err != nil {
+		return models.MealYield{}, internal.ErrWrongBody
+	}
+	if _, err = Microservices.GetMeal(userId, mealId); err != nil {
+		return models.MealYield{}, err
+	}
+	yield.UserId = userId
+	yield.MealId = mealId
+	if yield.Offset == 0 {
+		yield.Offset = 1
+	}
+	if err = m.db.SaveMealYield(yield); err != nil {
+		return models.MealYield{}, internal.ErrSomethingWentWrong
+	}
+	return m.db.GetMealYield(userId, mealId)
+}
+
+func (m *MealYieldManager) DeleteMealYield(userId, mealId string) (err error) {
+	if _, err = m.db.GetMealYield(userId, mealId); err != nil {
+		return
+	}
+	if err = m.db.DeleteMealYield(userId, mealId); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func mealYields(db *repositories.SQLiteMealYieldRepository, userId string) (yields map[string]models.MealYield, err error) {
+	list, err := db.GetMealYields(userId)
+	if err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	yields = make(map[string]models.MealYield, len(list))
+	for _, y := range list {
+		yields[y.MealId] = y
+	}
+	return
+}
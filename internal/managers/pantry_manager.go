@@ -0,0 +1,89 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/oklog/ulid/v2"
+	"strings"
+	"time"
+)
+
+type IPantryManager interface {
+	GetPantryItems(userId string) (items []models.PantryItem, err error)
+	CreatePantryItem(userId string, item models.PantryItem) (itemResponse models.PantryItem, err error)
+	UpdatePantryItem(userId, id string, item models.PantryItem) (itemResponse models.PantryItem, err error)
+	DeletePantryItem(userId, id string) (err error)
+}
+
+type PantryManager struct {
+	db       *repositories.SQLitePantryRepository
+	validate *validator.Validate
+}
+
+func NewPantryManager(db database.Database) *PantryManager {
+	return &PantryManager{
+		db:       repositories.NewSQLitePantryRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (p *PantryManager) GetPantryItems(userId string) (items []models.PantryItem, err error) {
+	if items, err = p.db.GetPantryItems(userId); err != nil {
+		return []models.PantryItem{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (p *PantryManager) CreatePantryItem(userId string, item models.PantryItem) (itemResponse models.PantryItem, err error) {
+	if err = p.validatePantryItem(&item); err != nil {
+		return
+	}
+	item.Id = ulid.Make().String()
+	item.UserId = userId
+	if err = p.db.CreatePantryItem(item); err != nil {
+		return models.PantryItem{}, internal.ErrSomethingWentWrong
+	}
+	return p.db.GetPantryItem(userId, item.Id)
+}
+
+func (p *PantryManager) UpdatePantryItem(userId, id string, item models.PantryItem) (itemResponse models.PantryItem, err error) {
+	if _, err = p.db.GetPantryItem(userId, id); err != nil {
+		return
+	}
+	if err = p.validatePantryItem(&item); err != nil {
+		return
+	}
+	item.Id = id
+	item.UserId = userId
+	if err = p.db.UpdatePantryItem(item); err != nil {
+		return models.PantryItem{}, internal.ErrSomethingWentWrong
+	}
+	return p.db.GetPantryItem(userId, id)
+}
+
+func (p *PantryManager) DeletePantryItem(userId, id string) (err error) {
+	if _, err = p.db.GetPantryItem(userId, id); err != nil {
+		return
+	}
+	if err = p.db.DeletePantryItem(userId, id); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// validatePantryItem checks the item and the format of its optional expiry date.
+func (p *PantryManager) validatePantryItem(item *models.PantryItem) (err error) {
+	item.Ingredient = strings.TrimSpace(item.Ingredient)
+	if err = p.validate.Struct(item); err != nil {
+		return internal.ErrWrongBody
+	}
+	if item.Expiry != "" {
+		if _, err = time.Parse("2006/01/02", item.Expiry); err != nil {
+			return internal.ErrInvalidDateFormat
+		}
+	}
+	return
+}
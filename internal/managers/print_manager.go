@@ -0,0 +1,81 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/utils"
+	"calendar/pkg/database"
+	"time"
+)
+
+// maxPrintWeeks is the number of weeks that can be printed at once, the weeks of
+// the calendar.
+const maxPrintWeeks = 4
+
+type IPrintManager interface {
+	PrintCalendar(userId string, options models.PrintOptions) (data []byte, err error)
+}
+
+type PrintManager struct {
+	calendar *CalendarManager
+	shopping *ShoppingListManager
+}
+
+func NewPrintManager(db database.Database) *PrintManager {
+	return &PrintManager{
+		calendar: NewCalendarManager(db),
+		shopping: NewShoppingListManager(db),
+	}
+}
+
+// PrintCalendar returns the weeks of the calendar, moved to the current window,
+// from the current one as a printable HTML page or PDF, followed by the
+// ingredients of the meals or the shopping list of those weeks when asked for.
+// The meals that cannot be fetched are listed without their ingredients.
+func (p *PrintManager) PrintCalendar(userId string, options models.PrintOptions) (data []byte, err error) {
+	if options.Format == "" {
+		options.Format = models.PrintHTML
+	}
+	if options.Format != models.PrintHTML && options.Format != models.PrintPDF {
+		return nil, internal.ErrUnsupportedFormat
+	}
+	if options.Weeks == 0 {
+		options.Weeks = 1
+	}
+	if options.Weeks < 0 || options.Weeks > maxPrintWeeks ||
+		(options.List != "" && options.List != models.PrintIngredients && options.List != models.PrintShopping) {
+		return nil, internal.ErrInvalidPrintOptions
+	}
+	calendar, err := p.calendar.GetCalendar(userId)
+	if err != nil {
+		return
+	}
+	plan := utils.PrintPlanOf(calendar, time.Now(), options.Weeks)
+	first, last := plan.Weeks[0].Start, plan.Weeks[len(plan.Weeks)-1].Days[6].Date
+	switch options.List {
+	case models.PrintIngredients:
+		var days []models.Calendar
+		for _, day := range calendar {
+			if day.Date >= first && day.Date <= last && day.MealId != "" && day.LeftoverOf == "" {
+				days = append(days, day)
+			}
+		}
+		meals, _ := getMeals(userId, days)
+		plan.Meals = utils.PrintMealsOf(days, meals)
+	case models.PrintShopping:
+		list, errList := p.shopping.GetShoppingList(userId, first, last)
+		if errList != nil {
+			return nil, errList
+		}
+		plan.Shopping = &list
+	}
+	if options.Format == models.PrintPDF {
+		data, err = utils.PrintPDF(plan)
+	} else {
+		data, err = utils.PrintHTML(plan)
+	}
+	if err != nil {
+		return nil, internal.ErrSomethingWentWrong
+	}
+	return
+}
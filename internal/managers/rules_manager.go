@@ -0,0 +1,96 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"calendar/pkg/rrule"
+	"github.com/go-playground/validator/v10"
+	"github.com/oklog/ulid/v2"
+	"time"
+)
+
+type IRuleManager interface {
+	GetRules(userId string) (rules []models.RecurringRule, err error)
+	CreateRule(userId string, rule models.RecurringRule) (ruleResponse models.RecurringRule, err error)
+	UpdateRule(userId, id string, rule models.RecurringRule) (ruleResponse models.RecurringRule, err error)
+	DeleteRule(userId, id string) (err error)
+}
+
+type RuleManager struct {
+	db       *repositories.SQLiteRuleRepository
+	validate *validator.Validate
+}
+
+func NewRuleManager(db database.Database) *RuleManager {
+	return &RuleManager{
+		db:       repositories.NewSQLiteRuleRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (r *RuleManager) GetRules(userId string) (rules []models.RecurringRule, err error) {
+	if rules, err = r.db.GetRules(userId); err != nil {
+		return []models.RecurringRule{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (r *RuleManager) CreateRule(userId string, rule models.RecurringRule) (ruleResponse models.RecurringRule, err error) {
+	if rule, err = r.validateRule(userId, rule); err != nil {
+		return
+	}
+	rule.Id = ulid.Make().String()
+	if err = r.db.CreateRule(rule); err != nil {
+		return models.RecurringRule{}, internal.ErrSomethingWentWrong
+	}
+	return r.db.GetRule(userId, rule.Id)
+}
+
+func (r *RuleManager) UpdateRule(userId, id string, rule models.RecurringRule) (ruleResponse models.RecurringRule, err error) {
+	if _, err = r.db.GetRule(userId, id); err != nil {
+		return
+	}
+	if rule, err = r.validateRule(userId, rule); err != nil {
+		return
+	}
+	rule.Id = id
+	if err = r.db.UpdateRule(rule); err != nil {
+		return models.RecurringRule{}, internal.ErrSomethingWentWrong
+	}
+	return r.db.GetRule(userId, id)
+}
+
+func (r *RuleManager) DeleteRule(userId, id string) (err error) {
+	if _, err = r.db.GetRule(userId, id); err != nil {
+		return
+	}
+	if err = r.db.DeleteRule(userId, id); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// validateRule checks the rule and its meal, and fills the user and the start
+// date when they are not given.
+func (r *RuleManager) validateRule(userId string, rule models.RecurringRule) (models.RecurringRule, error) {
+	if err := r.validate.Struct(rule); err != nil {
+		return rule, internal.ErrWrongBody
+	}
+	if rule.Start == "" {
+		rule.Start = time.Now().Format("2006/01/02")
+	}
+	start, err := time.Parse("2006/01/02", rule.Start)
+	if err != nil {
+		return rule, internal.ErrInvalidDateFormat
+	}
+	if _, err = rrule.Parse(rule.Rule, start); err != nil {
+		return rule, internal.ErrInvalidRule
+	}
+	if _, err = Microservices.GetMeal(userId, rule.MealId); err != nil {
+		return rule, err
+	}
+	rule.UserId = userId
+	return rule, nil
+}
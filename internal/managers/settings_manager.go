@@ -0,0 +1,69 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"calendar/pkg/holidays"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"io"
+)
+
+type ISettingsManager interface {
+	GetSettings(userId string) (settings models.UserSettings, err error)
+	UpdateSettings(userId string, settings models.UserSettings) (settingsResponse models.UserSettings, err error)
+	PatchSettings(userId string, patch io.Reader) (settingsResponse models.UserSettings, err error)
+}
+
+type SettingsManager struct {
+	db       *repositories.SQLiteSettingsRepository
+	validate *validator.Validate
+}
+
+func NewSettingsManager(db database.Database) *SettingsManager {
+	return &SettingsManager{
+		db:       repositories.NewSQLiteSettingsRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (s *SettingsManager) GetSettings(userId string) (settings models.UserSettings, err error) {
+	if settings, err = s.db.GetSettings(userId); err != nil {
+		return models.UserSettings{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (s *SettingsManager) UpdateSettings(userId string, settings models.UserSettings) (settingsResponse models.UserSettings, err error) {
+	if err = s.validate.Struct(settings); err != nil {
+		return models.UserSettings{}, internal.ErrWrongBody
+	}
+	if settings.HolidayCountry != "" || settings.HolidayRegion != "" {
+		if _, err = holidays.Load(settings.HolidayCountry, settings.HolidayRegion); err != nil {
+			return models.UserSettings{}, internal.ErrUnknownHolidays
+		}
+	}
+	settings.UserId = userId
+	if settings.DefaultServings == 0 {
+		settings.DefaultServings = models.NewUserSettings(userId).DefaultServings
+	}
+	if err = s.db.SaveSettings(settings); err != nil {
+		return models.UserSettings{}, internal.ErrSomethingWentWrong
+	}
+	return s.GetSettings(userId)
+}
+
+// PatchSettings decodes the JSON patch over the current settings of the user,
+// so the fields not in it keep their value, and saves the result.
+func (s *SettingsManager) PatchSettings(userId string, patch io.Reader) (settingsResponse models.UserSettings, err error) {
+	settings, err := s.GetSettings(userId)
+	if err != nil {
+		return
+	}
+	if err = json.NewDecoder(patch).Decode(&settings); err != nil {
+		return models.UserSettings{}, internal.ErrWrongBody
+	}
+	return s.UpdateSettings(userId, settings)
+}
@@ -0,0 +1,67 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/utils"
+	"calendar/pkg/database"
+	"time"
+)
+
+type IShoppingListManager interface {
+	GetShoppingList(userId, from, to string) (list models.ShoppingList, err error)
+}
+
+type ShoppingListManager struct {
+	calendar *CalendarManager
+}
+
+func NewShoppingListManager(db database.Database) *ShoppingListManager {
+	return &ShoppingListManager{
+		calendar: NewCalendarManager(db),
+	}
+}
+
+// GetShoppingList returns the shopping list of the meals planned between from and
+// to, both included, in the calendar moved to the current window. Without from
+// the list starts today, and without to it spans a week.
+func (s *ShoppingListManager) GetShoppingList(userId, from, to string) (list models.ShoppingList, err error) {
+	fromDate, toDate, err := dateRange(from, to, 6)
+	if err != nil {
+		return
+	}
+	calendar, err := s.calendar.GetCalendar(userId)
+	if err != nil {
+		return
+	}
+	from, to = fromDate.Format("2006/01/02"), toDate.Format("2006/01/02")
+	var days []models.Calendar
+	for _, day := range calendar {
+		if day.Date >= from && day.Date <= to && day.MealId != "" && day.LeftoverOf == "" {
+			days = append(days, day)
+		}
+	}
+	meals, _ := getMeals(userId, days)
+	return utils.ShoppingListOf(days, meals, from, to), nil
+}
+
+// dateRange parses the from and to dates (aaaa/MM/dd) of a request. from is
+// today when not given, and to is days after from.
+func dateRange(from, to string, days int) (fromDate, toDate time.Time, err error) {
+	fromDate, _ = time.Parse("2006/01/02", time.Now().Format("2006/01/02"))
+	if from != "" {
+		if fromDate, err = time.Parse("2006/01/02", from); err != nil {
+			return fromDate, toDate, internal.ErrInvalidDateFormat
+		}
+	}
+	toDate = fromDate.AddDate(0, 0, days)
+	if to != "" {
+		if toDate, err = time.Parse("2006/01/02", to); err != nil {
+			return fromDate, toDate, internal.ErrInvalidDateFormat
+		}
+	}
+	if toDate.Before(fromDate) {
+		return fromDate, toDate, internal.ErrInvalidDateRange
+	}
+	return fromDate, toDate, nil
+}
@@ -0,0 +1,89 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"calendar/pkg/holidays"
+	"github.com/go-playground/validator/v10"
+	"github.com/oklog/ulid/v2"
+	"io"
+	"strings"
+)
+
+type ISpecialDateManager interface {
+	GetSpecialDates(userId string) (dates []models.SpecialDate, err error)
+	CreateSpecialDate(userId string, date models.SpecialDate) (dateResponse models.SpecialDate, err error)
+	DeleteSpecialDate(userId, id string) (err error)
+	ImportSpecialDates(userId string, ics io.Reader) (dates []models.SpecialDate, err error)
+}
+
+type SpecialDateManager struct {
+	db       *repositories.SQLiteSpecialDateRepository
+	validate *validator.Validate
+}
+
+func NewSpecialDateManager(db database.Database) *SpecialDateManager {
+	return &SpecialDateManager{
+		db:       repositories.NewSQLiteSpecialDateRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (s *SpecialDateManager) GetSpecialDates(userId string) (dates []models.SpecialDate, err error) {
+	if dates, err = s.db.GetSpecialDates(userId); err != nil {
+		return []models.SpecialDate{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (s *SpecialDateManager) CreateSpecialDate(userId string, date models.SpecialDate) (dateResponse models.SpecialDate, err error) {
+	date.Date = strings.TrimSpace(date.Date)
+	if err = s.validate.Struct(date); err != nil {
+		return models.SpecialDate{}, internal.ErrWrongBody
+	}
+	if err = holidays.Validate(date.Date); err != nil {
+		return models.SpecialDate{}, internal.ErrInvalidSpecialDate
+	}
+	date.Id = ulid.Make().String()
+	date.UserId = userId
+	date.Source = models.SpecialDateCustom
+	if err = s.db.CreateSpecialDate(date); err != nil {
+		return models.SpecialDate{}, internal.ErrSomethingWentWrong
+	}
+	return s.db.GetSpecialDate(userId, date.Id)
+}
+
+func (s *SpecialDateManager) DeleteSpecialDate(userId, id string) (err error) {
+	if _, err = s.db.GetSpecialDate(userId, id); err != nil {
+		return
+	}
+	if err = s.db.DeleteSpecialDate(userId, id); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// ImportSpecialDates replaces the dates imported before with the events of the
+// iCalendar file. Dates created one by one are kept.
+func (s *SpecialDateManager) ImportSpecialDates(userId string, ics io.Reader) (dates []models.SpecialDate, err error) {
+	imported, err := holidays.ParseICS(ics)
+	if err != nil {
+		return []models.SpecialDate{}, internal.ErrInvalidICS
+	}
+	dates = make([]models.SpecialDate, 0, len(imported))
+	for _, h := range imported {
+		dates = append(dates, models.SpecialDate{
+			Id:     ulid.Make().String(),
+			UserId: userId,
+			Date:   h.Date,
+			Name:   h.Name,
+			Source: models.SpecialDateImported,
+		})
+	}
+	if err = s.db.ReplaceSpecialDates(userId, models.SpecialDateImported, dates); err != nil {
+		return []models.SpecialDate{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
@@ -0,0 +1,170 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/oklog/ulid/v2"
+	"time"
+)
+
+type ITemplateManager interface {
+	GetTemplates(userId string) (templates []models.Template, err error)
+	GetTemplate(userId, id string) (template models.Template, err error)
+	CreateTemplate(userId string, template models.Template) (templateResponse models.Template, err error)
+	UpdateTemplate(userId, id string, template models.Template) (templateResponse models.Template, err error)
+	DeleteTemplate(userId, id string) (err error)
+	ApplyTemplate(userId, id string, apply models.ApplyTemplate) (calendar []models.Calendar, err error)
+}
+
+type TemplateManager struct {
+	db         *repositories.SQLiteTemplateRepository
+	calendarDb *repositories.SQLiteCalendarRepository
+	calendars  *CalendarManager
+	validate   *validator.Validate
+}
+
+func NewTemplateManager(db database.Database) *TemplateManager {
+	return &TemplateManager{
+		db:         repositories.NewSQLiteTemplateRepository(&db),
+		calendarDb: repositories.NewSQLiteCalendarRepository(&db),
+		calendars:  NewCalendarManager(db),
+		validate:   validator.New(),
+	}
+}
+
+func (t *TemplateManager) GetTemplates(userId string) (templates []models.Template, err error) {
+	if templates, err = t.db.GetTemplates(userId); err != nil {
+		return []models.Template{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (t *TemplateManager) GetTemplate(userId, id string) (template models.Template, err error) {
+	if template, err = t.db.GetTemplate(userId, id); err != nil && !errors.Is(err, internal.ErrTemplateNotFound) {
+		return models.Template{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (t *TemplateManager) CreateTemplate(userId string, template models.Template) (templateResponse models.Template, err error) {
+	if err = t.validateTemplate(template); err != nil {
+		return
+	}
+	template.Id = ulid.Make().String()
+	template.UserId = userId
+	if err = t.db.CreateTemplate(template); err != nil {
+		return models.Template{}, internal.ErrSomethingWentWrong
+	}
+	return t.GetTemplate(userId, template.Id)
+}
+
+func (t *TemplateManager) UpdateTemplate(userId, id string, template models.Template) (templateResponse models.Template, err error) {
+	if _, err = t.GetTemplate(userId, id); err != nil {
+		return
+	}
+	if err = t.validateTemplate(template); err != nil {
+		return
+	}
+	template.Id = id
+	template.UserId = userId
+	if err = t.db.UpdateTemplate(template); err != nil {
+		return models.Template{}, internal.ErrSomethingWentWrong
+	}
+	return t.GetTemplate(userId, id)
+}
+
+func (t *TemplateManager) DeleteTemplate(userId, id string) (err error) {
+	if _, err = t.GetTemplate(userId, id); err != nil {
+		return
+	}
+	if err = t.db.DeleteTemplate(userId, id); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+// ApplyTemplate fills the week starting at apply.From with the template. Days
+// whose meal can not be resolved, is excluded, or that only fix the type of
+// meal, are chosen with ReturnRandomMeal. Days of the week outside the calendar are skipped.
+// Leftovers of the overwritten meals are kept, unlinked. The calendar is moved to
+// the current window before the template is applied.
+func (t *TemplateManager) ApplyTemplate(userId, id string, apply models.ApplyTemplate) (calendar []models.Calendar, err error) {
+	from, err := time.Parse("2006/01/02", apply.From)
+	if err != nil {
+		return []models.Calendar{}, internal.ErrInvalidDateFormat
+	}
+	template, err := t.GetTemplate(userId, id)
+	if err != nil {
+		return
+	}
+	if calendar, err = t.calendars.GetCalendar(userId); err != nil {
+		return
+	}
+	if _, err = t.calendarDb.GetCalendarSpecificDate(userId, apply.From); err != nil {
+		return
+	}
+	meals, err := t.calendars.availableMeals(userId, from, from.AddDate(0, 0, 6))
+	if err != nil {
+		return
+	}
+	if len(meals) == 0 {
+		return calendar, internal.ErrMealsNotFound
+	}
+	tools, err := t.calendars.calendarTools(userId, meals)
+	if err != nil {
+		return calendar, err
+	}
+
+	slots := make(map[int]models.TemplateDay, len(template.Days))
+	for _, d := range template.Days {
+		slots[d.Weekday] = d
+	}
+	positions := make(map[string]int, len(calendar))
+	for i, c := range calendar {
+		positions[c.Date] = i
+	}
+	var days []models.Calendar
+	var dates []string
+	for i := 0; i < 7; i++ {
+		date := from.AddDate(0, 0, i)
+		pos, ok := positions[date.Format("2006/01/02")]
+		if !ok {
+			continue
+		}
+		slot := slots[int(date.Weekday())]
+		meal, ok := resolveMeal(userId, slot.MealId, meals)
+		if !ok || tools.Excluded(&meal, date) {
+			candidates := tools.FilterMealsByType(meals, slot.MealType)
+			if len(candidates) == 0 {
+				candidates = meals
+			}
+			meal = tools.ReturnRandomMeal(calendar, candidates, date)
+		}
+		calendar[pos] = models.Calendar{UserId: userId, MealId: meal.Id, Name: meal.Name, Kcal: meal.Kcal, Date: calendar[pos].Date, Servings: calendar[pos].Servings}
+		days = append(days, calendar[pos])
+		dates = append(dates, calendar[pos].Date)
+	}
+	days = append(days, tools.UnlinkLeftovers(calendar, dates)...)
+	if err = t.calendarDb.UpdateCalendarDays(userId, days); err != nil {
+		return calendar, internal.ErrSomethingWentWrong
+	}
+	return t.calendars.GetCalendar(userId)
+}
+
+func (t *TemplateManager) validateTemplate(template models.Template) (err error) {
+	if err = t.validate.Struct(template); err != nil {
+		return internal.ErrWrongBody
+	}
+	weekdays := map[int]bool{}
+	for _, d := range template.Days {
+		if weekdays[d.Weekday] {
+			return internal.ErrWrongBody
+		}
+		weekdays[d.Weekday] = true
+	}
+	return
+}
@@ -0,0 +1,73 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"github.com/go-playground/validator/v10"
+	"github.com/oklog/ulid/v2"
+)
+
+type IThemeManager interface {
+	GetThemes(userId string) (themes []models.WeekdayTheme, err error)
+	CreateTheme(userId string, theme models.WeekdayTheme) (themeResponse models.WeekdayTheme, err error)
+	UpdateTheme(userId, id string, theme models.WeekdayTheme) (themeResponse models.WeekdayTheme, err error)
+	DeleteTheme(userId, id string) (err error)
+}
+
+type ThemeManager struct {
+	db       *repositories.SQLiteThemeRepository
+	validate *validator.Validate
+}
+
+func NewThemeManager(db database.Database) *ThemeManager {
+	return &ThemeManager{
+		db:       repositories.NewSQLiteThemeRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+func (t *ThemeManager) GetThemes(userId string) (themes []models.WeekdayTheme, err error) {
+	if themes, err = t.db.GetThemes(userId); err != nil {
+		return []models.WeekdayTheme{}, internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func (t *ThemeManager) CreateTheme(userId string, theme models.WeekdayTheme) (themeResponse models.WeekdayTheme, err error) {
+	if err = t.validate.Struct(theme); err != nil {
+		return models.WeekdayTheme{}, internal.ErrWrongBody
+	}
+	theme.Id = ulid.Make().String()
+	theme.UserId = userId
+	if err = t.db.CreateTheme(theme); err != nil {
+		return models.WeekdayTheme{}, internal.ErrSomethingWentWrong
+	}
+	return t.db.GetTheme(userId, theme.Id)
+}
+
+func (t *ThemeManager) UpdateTheme(userId, id string, theme models.WeekdayTheme) (themeResponse models.WeekdayTheme, err error) {
+	if _, err = t.db.GetTheme(userId, id); err != nil {
+		return
+	}
+	if err = t.validate.Struct(theme); err != nil {
+		return models.WeekdayTheme{}, internal.ErrWrongBody
+	}
+	theme.Id = id
+	theme.UserId = userId
+	if err = t.db.UpdateTheme(theme); err != nil {
+		return models.WeekdayTheme{}, internal.ErrSomethingWentWrong
+	}
+	return t.db.GetTheme(userId, id)
+}
+
+func (t *ThemeManager) DeleteTheme(userId, id string) (err error) {
+	if _, err = t.db.GetTheme(userId, id); err != nil {
+		return
+	}
+	if err = t.db.DeleteTheme(userId, id); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
@@ -0,0 +1,90 @@
+package managers
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/internal/repositories"
+	"calendar/pkg/database"
+	"github.com/go-playground/validator/v10"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// mealTypePattern are the meal types a policy can be set for: words of letters
+// and digits separated by a space, a hyphen or an underscore.
+var mealTypePattern = regexp.MustCompile(`^[\p{L}\p{N}]+([ _-][\p{L}\p{N}]+)*$`)
+
+type ITypePolicyManager interface {
+	GetTypePolicies(userId string) (policies []models.TypePolicy, err error)
+	UpdateTypePolicy(userId, mealType string, policy models.TypePolicy) (policyResponse models.TypePolicy, err error)
+	DeleteTypePolicy(userId, mealType string) (err error)
+}
+
+type TypePolicyManager struct {
+	db       *repositories.SQLiteTypePolicyRepository
+	validate *validator.Validate
+}
+
+func NewTypePolicyManager(db database.Database) *TypePolicyManager {
+	return &TypePolicyManager{
+		db:       repositories.NewSQLiteTypePolicyRepository(&db),
+		validate: validator.New(),
+	}
+}
+
+// GetTypePolicies returns the default policies, replaced by the ones changed by
+// the user, plus the policies of the types added by the user.
+func (t *TypePolicyManager) GetTypePolicies(userId string) (policies []models.TypePolicy, err error) {
+	return typePolicies(t.db, userId)
+}
+
+func (t *TypePolicyManager) UpdateTypePolicy(userId, mealType string, policy models.TypePolicy) (policyResponse models.TypePolicy, err error) {
+	mealType = strings.ToLower(strings.TrimSpace(mealType))
+	if !mealTypePattern.MatchString(mealType) {
+		return models.TypePolicy{}, internal.ErrMealTypeNotPresent
+	}
+	if err = t.validate.Struct(policy); err != nil {
+		return models.TypePolicy{}, internal.ErrWrongBody
+	}
+	policy.UserId = userId
+	policy.Type = mealType
+	if err = t.db.SaveTypePolicy(policy); err != nil {
+		return models.TypePolicy{}, internal.ErrSomethingWentWrong
+	}
+	return t.db.GetTypePolicy(userId, policy.Type)
+}
+
+// DeleteTypePolicy removes the policy of the user, going back to the default
+// one if the type has it.
+func (t *TypePolicyManager) DeleteTypePolicy(userId, mealType string) (err error) {
+	mealType = strings.ToLower(strings.TrimSpace(mealType))
+	if _, err = t.db.GetTypePolicy(userId, mealType); err != nil {
+		return
+	}
+	if err = t.db.DeleteTypePolicy(userId, mealType); err != nil {
+		return internal.ErrSomethingWentWrong
+	}
+	return
+}
+
+func typePolicies(db *repositories.SQLiteTypePolicyRepository, userId string) (policies []models.TypePolicy, err error) {
+	userPolicies, err := db.GetTypePolicies(userId)
+	if err != nil {
+		return []models.TypePolicy{}, internal.ErrSomethingWentWrong
+	}
+	byType := map[string]models.TypePolicy{}
+	for _, p := range models.DefaultTypePolicies() {
+		p.UserId = userId
+		byType[p.Type] = p
+	}
+	for _, p := range userPolicies {
+		byType[p.Type] = p
+	}
+	policies = make([]models.TypePolicy, 0, len(byType))
+	for _, p := range byType {
+		policies = append(policies, p)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Type < policies[j].Type })
+	return
+}
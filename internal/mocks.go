@@ -9,8 +9,8 @@ type EndpointsMock struct {
 	mock.Mock
 }
 
-func (e *EndpointsMock) GetAllMeals(userId string) (meals []*models.MealToFront, err error) {
-	args := e.Called(userId)
+func (e *EndpointsMock) GetAllMeals(userId string, seasons []string) (meals []*models.MealToFront, err error) {
+	args := e.Called(userId, seasons)
 	return args.Get(0).([]*models.MealToFront), args.Error(1)
 }
 
@@ -0,0 +1,11 @@
+package models
+
+// CalDAVEvent is the calendar object resource of a day of the calendar with a
+// meal, named after its date as yyyymmdd.ics in the CalDAV collection of the
+// user. ETag changes along with Data.
+type CalDAVEvent struct {
+	Name string
+	Date string
+	ETag string
+	Data []byte
+}
@@ -6,11 +6,22 @@ const (
 	Ocasional = "ocasional"
 )
 
+// General is the season of the meals available all year long.
+const General = "general"
+
+// NoMeal is the name of the days of the calendar without a meal.
+const NoMeal = "NO MEAL"
+
 type Calendar struct {
 	UserId string `db:"user_id" json:"user_id"`
 	MealId string `db:"meal_id" json:"meal_id"`
 	Name   string `json:"name" json:"name"`
 	Date   string `db:"date" json:"date"`
+	Kcal   int    `db:"kcal" json:"kcal"`
+	// LeftoverOf is the date the meal of a leftovers day is cooked.
+	LeftoverOf string `db:"leftover_of" json:"leftover_of,omitempty"`
+	// Servings is how many people eat that day. Zero keeps the current ones.
+	Servings int `db:"servings" json:"servings" validate:"min=0"`
 }
 
 type UpdateWeekCalendar struct {
@@ -18,6 +29,26 @@ type UpdateWeekCalendar struct {
 	To   string `json:"to"`
 }
 
+type CopyWeekCalendar struct {
+	From         string `json:"from"`
+	To           string `json:"to"`
+	CheckRepeats bool   `json:"check_repeats"`
+}
+
+type CopyWeekResponse struct {
+	Calendar []Calendar      `json:"calendar"`
+	Warnings []RepeatWarning `json:"warnings,omitempty"`
+	Weeks    []WeekSummary   `json:"weeks,omitempty"`
+}
+
+// RepeatWarning flags a day whose meal is repeated too close to another day.
+type RepeatWarning struct {
+	Date     string  `json:"date"`
+	MealId   string  `json:"meal_id"`
+	Name     string  `json:"name"`
+	Distance float64 `json:"distance"`
+}
+
 //definitions for endpoint calls//
 
 type User struct {
@@ -33,7 +64,7 @@ type Meal struct {
 	Name        string `db:"name" json:"name" validate:"required"`
 	Description string `db:"description" json:"description,omitempty"`
 	Image       string `db:"image" json:"image,omitempty"`
-	Type        string `db:"type" json:"type" validate:"required,oneof=semanal ocasional normal"`
+	Type        string `db:"type" json:"type" validate:"required"`
 	Ingredients string `db:"ingredients" json:"ingredients" validate:"required"`
 	Kcal        int    `db:"kcal" json:"kcal"`
 	Seasons     string `db:"seasons" json:"seasons"`
@@ -47,10 +78,23 @@ type MealToFront struct {
 	Name        string   `json:"name" validate:"required"`
 	Description string   `json:"description"`
 	Image       string   `json:"image"`
-	Type        string   `json:"type" validate:"required,oneof=semanal ocasional normal"`
+	Type        string   `json:"type" validate:"required"`
 	Ingredients []string `json:"ingredients" validate:"required"`
 	Kcal        int      `json:"kcal"`
 	Seasons     []string `json:"seasons" validate:"required,dive,oneof=verano invierno primavera otoño general"`
+	Tags        []string `json:"tags,omitempty"`
+	// PrepTime is the preparation time in minutes, 0 when unknown.
+	PrepTime int `json:"prep_time,omitempty"`
 	//Creator     int      `json:"creator"`
 	//Saves       int      `json:"saves"`
 }
+
+// MealYield is how many days a meal of the user feeds. The meal is cooked the
+// day it is planned and eaten as leftovers the Days-1 following days, starting
+// Offset days after it (1 when not given).
+type MealYield struct {
+	UserId string `db:"user_id" json:"user_id"`
+	MealId string `db:"meal_id" json:"meal_id"`
+	Days   int    `db:"days" json:"days" validate:"min=2,max=7"`
+	Offset int    `db:"offset_days" json:"offset" validate:"min=0,max=6"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// MealCost is the cost the user estimates for a meal. It takes precedence over
+// the cost estimated from the prices of its ingredients.
+type MealCost struct {
+	UserId string  `db:"user_id" json:"user_id"`
+	MealId string  `db:"meal_id" json:"meal_id"`
+	Cost   float64 `db:"cost" json:"cost" validate:"min=0"`
+}
+
+// IngredientPrice is what the user pays for Quantity of Unit of an ingredient,
+// as "2.5 per 1 kg of arroz". Without unit the price is per piece, and without
+// quantity it is per 1 of the unit.
+type IngredientPrice struct {
+	Id         string  `db:"id" json:"id"`
+	UserId     string  `db:"user_id" json:"user_id"`
+	Ingredient string  `db:"ingredient" json:"ingredient" validate:"required"`
+	Price      float64 `db:"price" json:"price" validate:"min=0"`
+	Quantity   float64 `db:"quantity" json:"quantity,omitempty" validate:"min=0"`
+	Unit       string  `db:"unit" json:"unit,omitempty"`
+}
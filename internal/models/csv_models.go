@@ -0,0 +1,24 @@
+package models
+
+// CalendarCSVRow is a day of an imported CSV of the calendar, with the line of
+// the file it is on.
+type CalendarCSVRow struct {
+	Row    int
+	Date   string
+	MealId string
+}
+
+// CalendarCSVImport reports the import of a CSV into the calendar: the number of
+// rows written, the rows rejected and the calendar after the import.
+type CalendarCSVImport struct {
+	Imported int           `json:"imported"`
+	Errors   []CSVRowError `json:"errors"`
+	Calendar []Calendar    `json:"calendar"`
+}
+
+type CSVRowError struct {
+	Row     int    `json:"row"`
+	Date    string `json:"date,omitempty"`
+	MealId  string `json:"meal_id,omitempty"`
+	Message string `json:"message"`
+}
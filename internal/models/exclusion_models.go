@@ -0,0 +1,13 @@
+package models
+
+// IngredientExclusion removes the meals containing the ingredient from the
+// calendar. From and Until (aaaa/MM/dd, both included) limit it to a period of
+// time, without them the exclusion is permanent.
+type IngredientExclusion struct {
+	Id         string `db:"id" json:"id"`
+	UserId     string `db:"user_id" json:"user_id"`
+	Ingredient string `db:"ingredient" json:"ingredient" validate:"required"`
+	From       string `db:"from_date" json:"from,omitempty"`
+	Until      string `db:"until_date" json:"until,omitempty"`
+	Reason     string `db:"reason" json:"reason,omitempty"`
+}
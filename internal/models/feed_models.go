@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// FeedToken is the secret token of the iCalendar subscription feed of a user.
+// Only its hash is stored, the token is returned once when it is created or
+// rotated, along with the URL of the feed.
+type FeedToken struct {
+	UserId    string `db:"user_id" json:"user_id"`
+	Hash      string `db:"token_hash" json:"-"`
+	Token     string `db:"-" json:"token,omitempty"`
+	URL       string `db:"-" json:"url,omitempty"`
+	CreatedAt string `db:"created_at" json:"created_at"`
+}
+
+// FeedVersion is the version of the feed of a user last served with the same
+// options, which is modified when its content changes.
+type FeedVersion struct {
+	UserId     string `db:"user_id"`
+	Options    string `db:"options"`
+	ETag       string `db:"etag"`
+	ModifiedAt string `db:"modified_at"`
+}
+
+// Feed is the iCalendar of a subscription feed with its version.
+type Feed struct {
+	Data     []byte
+	ETag     string
+	Modified time.Time
+}
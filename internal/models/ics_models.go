@@ -0,0 +1,44 @@
+package models
+
+// ICSOptions are the options of the iCalendar export of the calendar. Without
+// MealTime (HH:MM) every day is an all-day event, with it the meal lasts
+// Duration minutes, 60 when not given. Ingredients adds the ingredients of the
+// meal to the description of the event.
+type ICSOptions struct {
+	MealTime    string
+	Duration    int
+	Ingredients bool
+}
+
+const (
+	// ImportMerge writes the imported days over the days of the calendar and
+	// ImportReplace replaces the whole calendar with them.
+	ImportMerge   = "merge"
+	ImportReplace = "replace"
+)
+
+// CalendarImport reports the import of an iCalendar into the calendar: the days
+// imported, with the meal their event matched, and the events left out.
+type CalendarImport struct {
+	Mode      string           `json:"mode"`
+	Imported  []ImportedDay    `json:"imported"`
+	Unmatched []UnmatchedEvent `json:"unmatched"`
+	Calendar  []Calendar       `json:"calendar"`
+}
+
+// ImportedDay is an event imported into the calendar. Fuzzy is true when the
+// summary of the event is similar to the name of the meal but not the same.
+type ImportedDay struct {
+	Date    string `json:"date"`
+	Summary string `json:"summary"`
+	MealId  string `json:"meal_id"`
+	Name    string `json:"name"`
+	Fuzzy   bool   `json:"fuzzy"`
+}
+
+// UnmatchedEvent is an event that could not be imported, and why.
+type UnmatchedEvent struct {
+	Date    string `json:"date,omitempty"`
+	Summary string `json:"summary"`
+	Reason  string `json:"reason"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// PantryItem is an ingredient the user has at home. Meals using it are favoured,
+// more so as its Expiry date (aaaa/MM/dd, optional) gets closer.
+type PantryItem struct {
+	Id         string  `db:"id" json:"id"`
+	UserId     string  `db:"user_id" json:"user_id"`
+	Ingredient string  `db:"ingredient" json:"ingredient" validate:"required"`
+	Quantity   float64 `db:"quantity" json:"quantity,omitempty" validate:"min=0"`
+	Unit       string  `db:"unit" json:"unit,omitempty"`
+	Expiry     string  `db:"expiry" json:"expiry,omitempty"`
+}
@@ -0,0 +1,53 @@
+package models
+
+const (
+	PrintHTML = "html"
+	PrintPDF  = "pdf"
+
+	// PrintIngredients adds a page with the ingredients of each meal and
+	// PrintShopping a page with the shopping list of the printed weeks.
+	PrintIngredients = "ingredients"
+	PrintShopping    = "shopping"
+)
+
+// PrintOptions are the options of the printable plan: its format, the number of
+// weeks from the current one and the list printed after them, if any.
+type PrintOptions struct {
+	Format string
+	Weeks  int
+	List   string
+}
+
+// PrintPlan is the plan laid out for printing, a week per row. From and To are
+// dd/MM/aaaa. Meals are only given with the ingredients list and Shopping with
+// the shopping list.
+type PrintPlan struct {
+	From     string
+	To       string
+	Weeks    []PrintWeek
+	Meals    []PrintMeal
+	Shopping *ShoppingList
+}
+
+type PrintWeek struct {
+	Start string
+	Days  []PrintDay
+}
+
+// PrintDay is a day of the printed plan, Meal is empty when it has none.
+type PrintDay struct {
+	Date     string
+	Weekday  string
+	Day      string
+	Meal     string
+	Leftover bool
+	Servings int
+}
+
+// PrintMeal is a meal of the ingredients list, Missing when it could not be
+// fetched.
+type PrintMeal struct {
+	Name        string
+	Ingredients []string
+	Missing     bool
+}
@@ -0,0 +1,12 @@
+package models
+
+// RecurringRule fixes a meal on the days matched by an RFC 5545 recurrence rule,
+// e.g. "FREQ=WEEKLY;BYDAY=FR" or "FREQ=MONTHLY;BYDAY=1SU". Start is the first day
+// the rule applies, today when not given.
+type RecurringRule struct {
+	Id     string `db:"id" json:"id"`
+	UserId string `db:"user_id" json:"user_id"`
+	MealId string `db:"meal_id" json:"meal_id" validate:"required"`
+	Rule   string `db:"rule" json:"rule" validate:"required"`
+	Start  string `db:"start" json:"start"`
+}
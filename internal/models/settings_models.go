@@ -0,0 +1,81 @@
+package models
+
+const (
+	North = "norte"
+	South = "sur"
+)
+
+// UserSettings are the per user preferences used when generating the calendar.
+// A zero value disables the corresponding limit.
+type UserSettings struct {
+	UserId        string `db:"user_id" json:"user_id"`
+	DailyKcalMin  int    `db:"daily_kcal_min" json:"daily_kcal_min" validate:"min=0"`
+	DailyKcalMax  int    `db:"daily_kcal_max" json:"daily_kcal_max" validate:"omitempty,gtefield=DailyKcalMin"`
+	WeeklyKcalMin int    `db:"weekly_kcal_min" json:"weekly_kcal_min" validate:"min=0"`
+	WeeklyKcalMax int    `db:"weekly_kcal_max" json:"weekly_kcal_max" validate:"omitempty,gtefield=WeeklyKcalMin"`
+	// IngredientSpacing is the minimum number of days between two meals sharing a
+	// main ingredient. MainIngredients are the ingredients considered main, when
+	// empty the first ingredient of every meal is its main ingredient.
+	IngredientSpacing int        `db:"ingredient_spacing" json:"ingredient_spacing" validate:"min=0"`
+	MainIngredients   StringList `db:"main_ingredients" json:"main_ingredients"`
+	// IngredientReuse is the weight of reusing ingredients within the same week,
+	// so fewer are bought. Zero does not take it into account.
+	IngredientReuse float64 `db:"ingredient_reuse" json:"ingredient_reuse" validate:"min=0,max=10"`
+	// Hemisphere decides the season of every date of the calendar.
+	Hemisphere string `db:"hemisphere" json:"hemisphere" validate:"omitempty,oneof=norte sur"`
+	// HolidayCountry and HolidayRegion select the bundled public holidays planned
+	// like weekend days. No holidays are used without a country.
+	HolidayCountry string `db:"holiday_country" json:"holiday_country"`
+	HolidayRegion  string `db:"holiday_region" json:"holiday_region"`
+	// MaxPrepTime is the maximum preparation time in minutes of each weekday, 0
+	// is Sunday. A zero value, or an empty list, sets no limit. Meals without
+	// preparation time are considered to take DefaultPrepTime minutes.
+	MaxPrepTime     IntList `db:"max_prep_time" json:"max_prep_time" validate:"len=0|len=7,dive,min=0"`
+	DefaultPrepTime int     `db:"default_prep_time" json:"default_prep_time" validate:"min=0"`
+	// DefaultServings are the servings of every new day of the calendar, 2 when
+	// not given. Days with HighServings or more, or with more than
+	// DefaultServings when it is zero, favour occasional meals by ServingsBonus.
+	DefaultServings int     `db:"default_servings" json:"default_servings" validate:"min=0"`
+	HighServings    int     `db:"high_servings" json:"high_servings" validate:"min=0"`
+	ServingsBonus   float64 `db:"servings_bonus" json:"servings_bonus" validate:"min=0"`
+	// WeeklyBudget caps the cost of the meals of every week, zero sets no cap.
+	// Meals are costed with the user's meal costs and ingredient prices.
+	WeeklyBudget float64 `db:"weekly_budget" json:"weekly_budget" validate:"min=0"`
+}
+
+// NewUserSettings returns the settings of a user that has not set any.
+func NewUserSettings(userId string) UserSettings {
+	return UserSettings{
+		UserId:            userId,
+		IngredientSpacing: 2,
+		MainIngredients:   StringList{},
+		Hemisphere:        North,
+		MaxPrepTime:       IntList{},
+		DefaultPrepTime:   30,
+		DefaultServings:   2,
+		ServingsBonus:     1.5,
+	}
+}
+
+// WeekSummary reports the totals of a calendar week (monday to sunday). Ranges
+// are scaled to the days of the week present in the calendar, and deviations
+// are zero inside the range, negative below it and positive above it.
+type WeekSummary struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Days          int    `json:"days"`
+	Kcal          int    `json:"kcal"`
+	KcalMin       int    `json:"kcal_min,omitempty"`
+	KcalMax       int    `json:"kcal_max,omitempty"`
+	KcalDeviation int    `json:"kcal_deviation"`
+	// Cost is the spend of the week, which leftovers do not add to, and Budget
+	// the user's weekly budget scaled the same way as the kcal range.
+	Cost            float64 `json:"cost"`
+	Budget          float64 `json:"budget,omitempty"`
+	BudgetDeviation float64 `json:"budget_deviation"`
+}
+
+type CalendarSummaryResponse struct {
+	Calendar []Calendar    `json:"calendar"`
+	Weeks    []WeekSummary `json:"weeks"`
+}
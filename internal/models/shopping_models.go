@@ -0,0 +1,29 @@
+package models
+
+// ShoppingList gathers the ingredients of the meals planned between From and To
+// (aaaa/MM/dd, both included), grouped by category. Leftovers days are not
+// counted, as their meal is cooked on another day.
+type ShoppingList struct {
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	Categories []ShoppingCategory `json:"categories"`
+	// Missing are the planned meals that could not be fetched from the meals
+	// service, so their ingredients are not in the list.
+	Missing []string `json:"missing,omitempty"`
+}
+
+type ShoppingCategory struct {
+	Name  string         `json:"name"`
+	Items []ShoppingItem `json:"items"`
+}
+
+// ShoppingItem is an ingredient of the list. Quantity and Unit are only given
+// when the ingredients of the meals state them, as in "200 g arroz", and are
+// added up. Count is the number of planned days using the ingredient.
+type ShoppingItem struct {
+	Name     string   `json:"name"`
+	Quantity float64  `json:"quantity,omitempty"`
+	Unit     string   `json:"unit,omitempty"`
+	Count    int      `json:"count"`
+	Meals    []string `json:"meals"`
+}
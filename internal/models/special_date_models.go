@@ -0,0 +1,18 @@
+package models
+
+const (
+	SpecialDateCustom   = "custom"
+	SpecialDateImported = "ics"
+)
+
+// SpecialDate is a day, such as a birthday or a local holiday, planned like a
+// weekend day. Date is "MM/dd" for every year, "aaaa/MM/dd" for a single day or
+// "easter+N" / "easter-N" for N days from Easter Sunday. Source tells the dates
+// created one by one from the ones imported from an iCalendar file.
+type SpecialDate struct {
+	Id     string `db:"id" json:"id"`
+	UserId string `db:"user_id" json:"user_id"`
+	Date   string `db:"date" json:"date" validate:"required"`
+	Name   string `db:"name" json:"name"`
+	Source string `db:"source" json:"source"`
+}
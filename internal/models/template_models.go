@@ -0,0 +1,22 @@
+package models
+
+type Template struct {
+	Id     string        `db:"id" json:"id"`
+	UserId string        `db:"user_id" json:"user_id"`
+	Name   string        `db:"name" json:"name" validate:"required"`
+	Days   []TemplateDay `json:"days" validate:"dive"`
+}
+
+// TemplateDay fixes the meal, or the type of meal, of a weekday (0 is Sunday).
+// A day with neither of them is filled randomly when the template is applied.
+type TemplateDay struct {
+	TemplateId string `db:"template_id" json:"-"`
+	UserId     string `db:"user_id" json:"-"`
+	Weekday    int    `db:"weekday" json:"weekday" validate:"min=0,max=6"`
+	MealId     string `db:"meal_id" json:"meal_id,omitempty"`
+	MealType   string `db:"meal_type" json:"meal_type,omitempty"`
+}
+
+type ApplyTemplate struct {
+	From string `json:"from"`
+}
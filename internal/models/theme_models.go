@@ -0,0 +1,22 @@
+package models
+
+const (
+	ThemeType       = "type"
+	ThemeIngredient = "ingredient"
+	ThemeTag        = "tag"
+)
+
+// WeekdayTheme constrains the meals of a weekday (0 is Sunday) by one of their
+// attributes, e.g. "Taco Tuesday" is {weekday: 2, attribute: tag, value: tacos}
+// and "Meatless Monday" is {weekday: 1, attribute: ingredient, value: carne,
+// exclude: true}. Strict themes filter the meals, the others only favour them.
+type WeekdayTheme struct {
+	Id        string `db:"id" json:"id"`
+	UserId    string `db:"user_id" json:"user_id"`
+	Weekday   int    `db:"weekday" json:"weekday" validate:"min=0,max=6"`
+	Name      string `db:"name" json:"name"`
+	Attribute string `db:"attribute" json:"attribute" validate:"required,oneof=type ingredient tag"`
+	Value     string `db:"value" json:"value" validate:"required"`
+	Exclude   bool   `db:"exclude" json:"exclude"`
+	Strict    bool   `db:"strict" json:"strict"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// TypePolicy decides how the meals of a type are planned. Zero values disable
+// the corresponding constraint.
+type TypePolicy struct {
+	UserId string `db:"user_id" json:"user_id"`
+	Type   string `db:"type" json:"type"`
+	// Frequency is the target number of meals of the type per week (monday to
+	// sunday).
+	Frequency int `db:"frequency" json:"frequency" validate:"min=0,max=7"`
+	// MinSpacing is the number of days between two servings of the same meal of
+	// the type from which it is favoured.
+	MinSpacing int `db:"min_spacing" json:"min_spacing" validate:"min=0"`
+	// Weekdays (0 is Sunday) are favoured for the type, the rest of the days are
+	// penalised. With Holidays, public holidays and special dates are favoured too.
+	Weekdays IntList `db:"weekdays" json:"weekdays" validate:"dive,min=0,max=6"`
+	Holidays bool    `db:"holidays" json:"holidays"`
+	// MaxCount is the maximum number of meals of the type in any Window
+	// consecutive days. Window defaults to 7.
+	MaxCount int `db:"max_count" json:"max_count" validate:"min=0"`
+	Window   int `db:"window_days" json:"window" validate:"min=0"`
+	// Default tells the policies that have not been changed by the user.
+	Default bool `db:"-" json:"default"`
+}
+
+// DefaultTypePolicies are the policies of the users that have not changed them:
+// weekly meals once every 7 days at most and occasional meals on weekends and
+// holidays.
+func DefaultTypePolicies() []TypePolicy {
+	return []TypePolicy{
+		{Type: Ocasional, Weekdays: IntList{0, 6}, Holidays: true, Default: true},
+		{Type: Semanal, MinSpacing: 7, Default: true},
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// StringList is a list of strings stored as a JSON array in a text column.
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	return string(b), err
+}
+
+func (l *StringList) Scan(src interface{}) error {
+	return scanJSONList(src, (*[]string)(l), "StringList")
+}
+
+// IntList is a list of integers stored as a JSON array in a text column.
+type IntList []int
+
+func (l IntList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]int(l))
+	return string(b), err
+}
+
+func (l *IntList) Scan(src interface{}) error {
+	return scanJSONList(src, (*[]int)(l), "IntList")
+}
+
+// scanJSONList decodes a JSON array column into list, leaving it empty when the
+// column is null or empty.
+func scanJSONList[T any](src interface{}, list *[]T, name string) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*list = []T{}
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return errors.New("unsupported type for " + name)
+	}
+	if len(b) == 0 {
+		*list = []T{}
+		return nil
+	}
+	return json.Unmarshal(b, list)
+}
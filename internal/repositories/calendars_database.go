@@ -4,14 +4,18 @@ import (
 	"calendar/internal"
 	"calendar/internal/models"
 	"calendar/pkg/database"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/gommon/log"
 )
 
 const (
 	getCalendar    = "SELECT * FROM calendar WHERE user_id = ? ORDER BY date"
-	updateCalendar = "UPDATE calendar SET meal_id = ?, name = ? WHERE user_id = ? AND date = ?"
-	createCalendar = "INSERT INTO calendar (meal_id,user_id,date,name) VALUES (?,?,?,?)"
+	updateCalendar = "UPDATE calendar SET meal_id = ?, name = ?, kcal = ?, leftover_of = ?, servings = ? WHERE user_id = ? AND date = ?"
+	createCalendar = "INSERT INTO calendar (meal_id,user_id,date,name,kcal,leftover_of,servings) VALUES (?,?,?,?,?,?,?)"
 	deleteCalendar = "DELETE FROM calendar WHERE user_id = ?"
+	mergeCalendar  = `INSERT INTO calendar (meal_id,user_id,date,name,kcal,leftover_of,servings) VALUES (?,?,?,?,?,?,?)
+	ON CONFLICT(user_id,date) DO UPDATE SET meal_id = excluded.meal_id, name = excluded.name, kcal = excluded.kcal,
+	leftover_of = excluded.leftover_of, servings = excluded.servings`
 
 	specificDateCalendar = "SELECT * FROM calendar WHERE user_id = ? AND date = ?"
 )
@@ -25,6 +29,9 @@ type DBCalendarI interface {
 	UpdateCalendar(id string, calendar models.Calendar) (err error)
 	CreateCalendar(calendar []models.Calendar) (err error)
 	DeleteCalendar(id string) (err error)
+	ReplaceCalendar(id string, calendar []models.Calendar) (err error)
+	UpdateCalendarDays(id string, days []models.Calendar) (err error)
+	MergeCalendar(calendar []models.Calendar) (err error)
 
 	GetCalendarSpecificDate(id, date string) (calendar []models.Calendar, err error)
 }
@@ -48,7 +55,7 @@ func (r *SQLiteCalendarRepository) GetCalendar(id string) (calendar []models.Cal
 }
 
 func (r *SQLiteCalendarRepository) UpdateCalendar(id string, c models.Calendar) (err error) {
-	_, err = r.db.Conn.Exec(updateCalendar, c.MealId, c.Name, id, c.Date)
+	_, err = r.db.Conn.Exec(updateCalendar, c.MealId, c.Name, c.Kcal, c.LeftoverOf, c.Servings, id, c.Date)
 	if err != nil {
 		log.Error(err)
 		return
@@ -58,7 +65,7 @@ func (r *SQLiteCalendarRepository) UpdateCalendar(id string, c models.Calendar)
 
 func (r *SQLiteCalendarRepository) CreateCalendar(calendar []models.Calendar) (err error) {
 	for _, c := range calendar {
-		_, err = r.db.Conn.Exec(createCalendar, c.MealId, c.UserId, c.Date, c.Name)
+		_, err = r.db.Conn.Exec(createCalendar, c.MealId, c.UserId, c.Date, c.Name, c.Kcal, c.LeftoverOf, c.Servings)
 		if err != nil {
 			log.Error(err)
 			return
@@ -76,6 +83,47 @@ func (r *SQLiteCalendarRepository) DeleteCalendar(id string) (err error) {
 	return
 }
 
+// ReplaceCalendar deletes the calendar of the user and stores the given one in a
+// single transaction, so the previous calendar is kept if anything fails.
+func (r *SQLiteCalendarRepository) ReplaceCalendar(id string, calendar []models.Calendar) (err error) {
+	return runInTx(r.db, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.Exec(deleteCalendar, id); err != nil {
+			return
+		}
+		for _, c := range calendar {
+			if _, err = tx.Exec(createCalendar, c.MealId, c.UserId, c.Date, c.Name, c.Kcal, c.LeftoverOf, c.Servings); err != nil {
+				return
+			}
+		}
+		return
+	})
+}
+
+// UpdateCalendarDays updates the meal of every given day in a single transaction.
+func (r *SQLiteCalendarRepository) UpdateCalendarDays(id string, days []models.Calendar) (err error) {
+	return runInTx(r.db, func(tx *sqlx.Tx) (err error) {
+		for _, c := range days {
+			if _, err = tx.Exec(updateCalendar, c.MealId, c.Name, c.Kcal, c.LeftoverOf, c.Servings, id, c.Date); err != nil {
+				return
+			}
+		}
+		return
+	})
+}
+
+// MergeCalendar stores every given day in a single transaction, replacing the
+// days of the calendar with the same date.
+func (r *SQLiteCalendarRepository) MergeCalendar(calendar []models.Calendar) (err error) {
+	return runInTx(r.db, func(tx *sqlx.Tx) (err error) {
+		for _, c := range calendar {
+			if _, err = tx.Exec(mergeCalendar, c.MealId, c.UserId, c.Date, c.Name, c.Kcal, c.LeftoverOf, c.Servings); err != nil {
+				return
+			}
+		}
+		return
+	})
+}
+
 func (r *SQLiteCalendarRepository) GetCalendarSpecificDate(id, date string) (calendar []models.Calendar, err error) {
 	err = r.db.Conn.Select(&calendar, specificDateCalendar, id, date)
 	if err != nil {
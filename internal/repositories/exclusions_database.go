@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getExclusions   = "SELECT * FROM ingredient_exclusions WHERE user_id = ? ORDER BY id"
+	getExclusion    = "SELECT * FROM ingredient_exclusions WHERE user_id = ? AND id = ?"
+	createExclusion = "INSERT INTO ingredient_exclusions (id,user_id,ingredient,from_date,until_date,reason) VALUES (?,?,?,?,?,?)"
+	updateExclusion = "UPDATE ingredient_exclusions SET ingredient = ?, from_date = ?, until_date = ?, reason = ? WHERE user_id = ? AND id = ?"
+	deleteExclusion = "DELETE FROM ingredient_exclusions WHERE user_id = ? AND id = ?"
+)
+
+type SQLiteExclusionRepository struct {
+	db *database.Database
+}
+
+type DBExclusionI interface {
+	GetExclusions(userId string) (exclusions []models.IngredientExclusion, err error)
+	GetExclusion(userId, id string) (exclusion models.IngredientExclusion, err error)
+	CreateExclusion(exclusion models.IngredientExclusion) (err error)
+	UpdateExclusion(exclusion models.IngredientExclusion) (err error)
+	DeleteExclusion(userId, id string) (err error)
+}
+
+func NewSQLiteExclusionRepository(db *database.Database) *SQLiteExclusionRepository {
+	return &SQLiteExclusionRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteExclusionRepository) GetExclusions(userId string) (exclusions []models.IngredientExclusion, err error) {
+	exclusions = []models.IngredientExclusion{}
+	if err = r.db.Conn.Select(&exclusions, getExclusions, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteExclusionRepository) GetExclusion(userId, id string) (exclusion models.IngredientExclusion, err error) {
+	var exclusions []models.IngredientExclusion
+	if err = r.db.Conn.Select(&exclusions, getExclusion, userId, id); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(exclusions) == 0 {
+		return models.IngredientExclusion{}, internal.ErrExclusionNotFound
+	}
+	return exclusions[0], nil
+}
+
+func (r *SQLiteExclusionRepository) CreateExclusion(exclusion models.IngredientExclusion) (err error) {
+	if _, err = r.db.Conn.Exec(createExclusion, exclusion.Id, exclusion.UserId, exclusion.Ingredient, exclusion.From, exclusion.Until, exclusion.Reason); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteExclusionRepository) UpdateExclusion(exclusion models.IngredientExclusion) (err error) {
+	if _, err = r.db.Conn.Exec(updateExclusion, exclusion.Ingredient, exclusion.From, exclusion.Until, exclusion.Reason, exclusion.UserId, exclusion.Id); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteExclusionRepository) DeleteExclusion(userId, id string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteExclusion, userId, id); err != nil {
+		log.Error(err)
+	}
+	return
+}
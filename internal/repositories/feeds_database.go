@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getFeedToken       = "SELECT * FROM feed_tokens WHERE user_id = ?"
+	getFeedTokenByHash = "SELECT * FROM feed_tokens WHERE token_hash = ?"
+	createFeedToken    = "INSERT INTO feed_tokens (user_id,token_hash,created_at) VALUES (?,?,?)"
+	updateFeedToken    = "UPDATE feed_tokens SET token_hash = ?, created_at = ? WHERE user_id = ?"
+	deleteFeedToken    = "DELETE FROM feed_tokens WHERE user_id = ?"
+	getFeedVersion     = "SELECT * FROM feed_versions WHERE user_id = ? AND options = ?"
+	saveFeedVersion    = `INSERT INTO feed_versions (user_id,options,etag,modified_at) VALUES (:user_id,:options,:etag,:modified_at)
+	ON CONFLICT(user_id,options) DO UPDATE SET etag = excluded.etag, modified_at = excluded.modified_at`
+	deleteFeedVersions = "DELETE FROM feed_versions WHERE user_id = ?"
+)
+
+type SQLiteFeedRepository struct {
+	db *database.Database
+}
+
+type DBFeedI interface {
+	GetFeedToken(userId string) (token models.FeedToken, err error)
+	GetFeedTokenByHash(hash string) (token models.FeedToken, err error)
+	CreateFeedToken(token models.FeedToken) (err error)
+	UpdateFeedToken(token models.FeedToken) (err error)
+	DeleteFeedToken(userId string) (err error)
+	GetFeedVersion(userId, options string) (version models.FeedVersion, err error)
+	SaveFeedVersion(version models.FeedVersion) (err error)
+}
+
+func NewSQLiteFeedRepository(db *database.Database) *SQLiteFeedRepository {
+	return &SQLiteFeedRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteFeedRepository) GetFeedToken(userId string) (token models.FeedToken, err error) {
+	return r.getFeedToken(getFeedToken, userId)
+}
+
+func (r *SQLiteFeedRepository) GetFeedTokenByHash(hash string) (token models.FeedToken, err error) {
+	return r.getFeedToken(getFeedTokenByHash, hash)
+}
+
+func (r *SQLiteFeedRepository) getFeedToken(query, arg string) (token models.FeedToken, err error) {
+	var tokens []models.FeedToken
+	if err = r.db.Conn.Select(&tokens, query, arg); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(tokens) == 0 {
+		return models.FeedToken{}, internal.ErrFeedTokenNotFound
+	}
+	return tokens[0], nil
+}
+
+func (r *SQLiteFeedRepository) CreateFeedToken(token models.FeedToken) (err error) {
+	if _, err = r.db.Conn.Exec(createFeedToken, token.UserId, token.Hash, token.CreatedAt); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteFeedRepository) UpdateFeedToken(token models.FeedToken) (err error) {
+	if _, err = r.db.Conn.Exec(updateFeedToken, token.Hash, token.CreatedAt, token.UserId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+// DeleteFeedToken deletes the token of the user along with the versions of its
+// feed.
+func (r *SQLiteFeedRepository) DeleteFeedToken(userId string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteFeedToken, userId); err != nil {
+		log.Error(err)
+		return
+	}
+	if _, err = r.db.Conn.Exec(deleteFeedVersions, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+// GetFeedVersion returns the version of the feed of the user last served with
+// the options, empty when it has not been served with them.
+func (r *SQLiteFeedRepository) GetFeedVersion(userId, options string) (version models.FeedVersion, err error) {
+	var versions []models.FeedVersion
+	if err = r.db.Conn.Select(&versions, getFeedVersion, userId, options); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(versions) == 0 {
+		return models.FeedVersion{UserId: userId, Options: options}, nil
+	}
+	return versions[0], nil
+}
+
+func (r *SQLiteFeedRepository) SaveFeedVersion(version models.FeedVersion) (err error) {
+	if _, err = r.db.Conn.NamedExec(saveFeedVersion, version); err != nil {
+		log.Error(err)
+	}
+	return
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getIngredientPrices   = "SELECT * FROM ingredient_prices WHERE user_id = ? ORDER BY id"
+	getIngredientPrice    = "SELECT * FROM ingredient_prices WHERE user_id = ? AND id = ?"
+	createIngredientPrice = "INSERT INTO ingredient_prices (id,user_id,ingredient,price,quantity,unit) VALUES (?,?,?,?,?,?)"
+	updateIngredientPrice = "UPDATE ingredient_prices SET ingredient = ?, price = ?, quantity = ?, unit = ? WHERE user_id = ? AND id = ?"
+	deleteIngredientPrice = "DELETE FROM ingredient_prices WHERE user_id = ? AND id = ?"
+)
+
+type SQLiteIngredientPriceRepository struct {
+	db *database.Database
+}
+
+type DBIngredientPriceI interface {
+	GetIngredientPrices(userId string) (prices []models.IngredientPrice, err error)
+	GetIngredientPrice(userId, id string) (price models.IngredientPrice, err error)
+	CreateIngredientPrice(price models.IngredientPrice) (err error)
+	UpdateIngredientPrice(price models.IngredientPrice) (err error)
+	DeleteIngredientPrice(userId, id string) (err error)
+}
+
+func NewSQLiteIngredientPriceRepository(db *database.Database) *SQLiteIngredientPriceRepository {
+	return &SQLiteIngredientPriceRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteIngredientPriceRepository) GetIngredientPrices(userId string) (prices []models.IngredientPrice, err error) {
+	prices = []models.IngredientPrice{}
+	if err = r.db.Conn.Select(&prices, getIngredientPrices, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteIngredientPriceRepository) GetIngredientPrice(userId, id string) (price models.IngredientPrice, err error) {
+	var prices []models.IngredientPrice
+	if err = r.db.Conn.Select(&prices, getIngredientPrice, userId, id); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(prices) == 0 {
+		return models.IngredientPrice{}, internal.ErrPriceNotFound
+	}
+	return prices[0], nil
+}
+
+func (r *SQLiteIngredientPriceRepository) CreateIngredientPrice(price models.IngredientPrice) (err error) {
+	if _, err = r.db.Conn.Exec(createIngredientPrice, price.Id, price.UserId, price.Ingredient, price.Price, price.Quantity, price.Unit); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteIngredientPriceRepository) UpdateIngredientPrice(price models.IngredientPrice) (err error) {
+	if _, err = r.db.Conn.Exec(updateIngredientPrice, price.Ingredient, price.Price, price.Quantity, price.Unit, price.UserId, price.Id); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteIngredientPriceRepository) DeleteIngredientPrice(userId, id string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteIngredientPrice, userId, id); err != nil {
+		log.Error(err)
+	}
+	return
+}
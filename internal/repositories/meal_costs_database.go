@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getMealCosts = "SELECT * FROM meal_costs WHERE user_id = ? ORDER BY meal_id"
+	getMealCost  = "SELECT * FROM meal_costs WHERE user_id = ? AND meal_id = ?"
+	saveMealCost = `INSERT INTO meal_costs (user_id,meal_id,cost) VALUES (:user_id,:meal_id,:cost)
+	ON CONFLICT(user_id,meal_id) DO UPDATE SET cost = excluded.cost`
+	deleteMealCost = "DELETE FROM meal_costs WHERE user_id = ? AND meal_id = ?"
+)
+
+type SQLiteMealCostRepository struct {
+	db *database.Database
+}
+
+type DBMealCostI interface {
+	GetMealCosts(userId string) (costs []models.MealCost, err error)
+	GetMealCost(userId, mealId string) (cost models.MealCost, err error)
+	SaveMealCost(cost models.MealCost) (err error)
+	DeleteMealCost(userId, mealId string) (err error)
+}
+
+func NewSQLiteMealCostRepository(db *database.Database) *SQLiteMealCostRepository {
+	return &SQLiteMealCostRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteMealCostRepository) GetMealCosts(userId string) (costs []models.MealCost, err error) {
+	costs = []models.MealCost{}
+	if err = r.db.Conn.Select(&costs, getMealCosts, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteMealCostRepository) GetMealCost(userId, mealId string) (cost models.MealCost, err error) {
+	var costs []models.MealCost
+	if err = r.db.Conn.Select(&costs, getMealCost, userId, mealId); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(costs) == 0 {
+		return models.MealCost{}, internal.ErrMealCostNotFound
+	}
+	return costs[0], nil
+}
+
+// SaveMealCost creates the cost of the meal or replaces the existing one.
+func (r *SQLiteMealCostRepository) SaveMealCost(cost models.MealCost) (err error) {
+	if _, err = r.db.Conn.NamedExec(saveMealCost, cost); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteMealCostRepository) DeleteMealCost(userId, mealId string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteMealCost, userId, mealId); err != nil {
+		log.Error(err)
+	}
+	return
+}
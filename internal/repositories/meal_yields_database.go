@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getMealYields = "SELECT * FROM meal_yields WHERE user_id = ? ORDER BY meal_id"
+	getMealYield  = "SELECT * FROM meal_yields WHERE user_id = ? AND meal_id = ?"
+	saveMealYield = `INSERT INTO meal_yields (user_id,meal_id,days,offset_days) VALUES (:user_id,:meal_id,:days,:offset_days)
+	ON CONFLICT(user_id,meal_id) DO UPDATE SET days = excluded.days, offset_days = excluded.offset_days`
+	deleteMealYield = "DELETE FROM meal_yields WHERE user_id = ? AND meal_id = ?"
+)
+
+type SQLiteMealYieldRepository struct {
+	db *database.Database
+}
+
+type DBMealYieldI interface {
+	GetMealYields(userId string) (yields []models.MealYield, err error)
+	GetMealYield(userId, mealId string) (yield models.MealYield, err error)
+	SaveMealYield(yield models.MealYield) (err error)
+	DeleteMealYield(userId, mealId string) (err error)
+}
+
+func NewSQLiteMealYieldRepository(db *database.Database) *SQLiteMealYieldRepository {
+	return &SQLiteMealYieldRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteMealYieldRepository) GetMealYields(userId string) (yields []models.MealYield, err error) {
+	yields = []models.MealYield{}
+	if err = r.db.Conn.Select(&yields, getMealYields, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteMealYieldRepository) GetMealYield(userId, mealId string) (yield models.MealYield, err error) {
+	var yields []models.MealYield
+	if err = r.db.Conn.Select(&yields, getMealYield, userId, mealId); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(yields) == 0 {
+		return models.MealYield{}, internal.ErrMealYieldNotFound
+	}
+	return yields[0], nil
+}
+
+// SaveMealYield creates the yield of the meal or replaces the existing one.
+func (r *SQLiteMealYieldRepository) SaveMealYield(yield models.MealYield) (err error) {
+	if _, err = r.db.Conn.NamedExec(saveMealYield, yield); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteMealYieldRepository) DeleteMealYield(userId, mealId string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteMealYield, userId, mealId); err != nil {
+		log.Error(err)
+	}
+	return
+}
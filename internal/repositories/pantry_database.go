@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getPantryItems   = "SELECT * FROM pantry WHERE user_id = ? ORDER BY id"
+	getPantryItem    = "SELECT * FROM pantry WHERE user_id = ? AND id = ?"
+	createPantryItem = "INSERT INTO pantry (id,user_id,ingredient,quantity,unit,expiry) VALUES (?,?,?,?,?,?)"
+	updatePantryItem = "UPDATE pantry SET ingredient = ?, quantity = ?, unit = ?, expiry = ? WHERE user_id = ? AND id = ?"
+	deletePantryItem = "DELETE FROM pantry WHERE user_id = ? AND id = ?"
+)
+
+type SQLitePantryRepository struct {
+	db *database.Database
+}
+
+type DBPantryI interface {
+	GetPantryItems(userId string) (items []models.PantryItem, err error)
+	GetPantryItem(userId, id string) (item models.PantryItem, err error)
+	CreatePantryItem(item models.PantryItem) (err error)
+	UpdatePantryItem(item models.PantryItem) (err error)
+	DeletePantryItem(userId, id string) (err error)
+}
+
+func NewSQLitePantryRepository(db *database.Database) *SQLitePantryRepository {
+	return &SQLitePantryRepository{
+		db: db,
+	}
+}
+
+func (r *SQLitePantryRepository) GetPantryItems(userId string) (items []models.PantryItem, err error) {
+	items = []models.PantryItem{}
+	if err = r.db.Conn.Select(&items, getPantryItems, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLitePantryRepository) GetPantryItem(userId, id string) (item models.PantryItem, err error) {
+	var items []models.PantryItem
+	if err = r.db.Conn.Select(&items, getPantryItem, userId, id); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(items) == 0 {
+		return models.PantryItem{}, internal.ErrPantryItemNotFound
+	}
+	return items[0], nil
+}
+
+func (r *SQLitePantryRepository) CreatePantryItem(item models.PantryItem) (err error) {
+	if _, err = r.db.Conn.Exec(createPantryItem, item.Id, item.UserId, item.Ingredient, item.Quantity, item.Unit, item.Expiry); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLitePantryRepository) UpdatePantryItem(item models.PantryItem) (err error) {
+	if _, err = r.db.Conn.Exec(updatePantryItem, item.Ingredient, item.Quantity, item.Unit, item.Expiry, item.UserId, item.Id); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLitePantryRepository) DeletePantryItem(userId, id string) (err error) {
+	if _, err = r.db.Conn.Exec(deletePantryItem, userId, id); err != nil {
+		log.Error(err)
+	}
+	return
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getRules   = "SELECT * FROM recurring_rules WHERE user_id = ? ORDER BY id"
+	getRule    = "SELECT * FROM recurring_rules WHERE user_id = ? AND id = ?"
+	createRule = "INSERT INTO recurring_rules (id,user_id,meal_id,rule,start) VALUES (?,?,?,?,?)"
+	updateRule = "UPDATE recurring_rules SET meal_id = ?, rule = ?, start = ? WHERE user_id = ? AND id = ?"
+	deleteRule = "DELETE FROM recurring_rules WHERE user_id = ? AND id = ?"
+)
+
+type SQLiteRuleRepository struct {
+	db *database.Database
+}
+
+type DBRuleI interface {
+	GetRules(userId string) (rules []models.RecurringRule, err error)
+	GetRule(userId, id string) (rule models.RecurringRule, err error)
+	CreateRule(rule models.RecurringRule) (err error)
+	UpdateRule(rule models.RecurringRule) (err error)
+	DeleteRule(userId, id string) (err error)
+}
+
+func NewSQLiteRuleRepository(db *database.Database) *SQLiteRuleRepository {
+	return &SQLiteRuleRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteRuleRepository) GetRules(userId string) (rules []models.RecurringRule, err error) {
+	rules = []models.RecurringRule{}
+	if err = r.db.Conn.Select(&rules, getRules, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteRuleRepository) GetRule(userId, id string) (rule models.RecurringRule, err error) {
+	var rules []models.RecurringRule
+	if err = r.db.Conn.Select(&rules, getRule, userId, id); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(rules) == 0 {
+		return models.RecurringRule{}, internal.ErrRuleNotFound
+	}
+	return rules[0], nil
+}
+
+func (r *SQLiteRuleRepository) CreateRule(rule models.RecurringRule) (err error) {
+	if _, err = r.db.Conn.Exec(createRule, rule.Id, rule.UserId, rule.MealId, rule.Rule, rule.Start); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteRuleRepository) UpdateRule(rule models.RecurringRule) (err error) {
+	if _, err = r.db.Conn.Exec(updateRule, rule.MealId, rule.Rule, rule.Start, rule.UserId, rule.Id); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteRuleRepository) DeleteRule(userId, id string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteRule, userId, id); err != nil {
+		log.Error(err)
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getSettings  = "SELECT * FROM user_settings WHERE user_id = ?"
+	saveSettings = `INSERT INTO user_settings (user_id,daily_kcal_min,daily_kcal_max,weekly_kcal_min,weekly_kcal_max,
+	ingredient_spacing,main_ingredients,hemisphere,holiday_country,holiday_region,max_prep_time,default_prep_time,
+	default_servings,high_servings,servings_bonus,ingredient_reuse,weekly_budget)
+	VALUES (:user_id,:daily_kcal_min,:daily_kcal_max,:weekly_kcal_min,:weekly_kcal_max,
+	:ingredient_spacing,:main_ingredients,:hemisphere,:holiday_country,:holiday_region,:max_prep_time,:default_prep_time,
+	:default_servings,:high_servings,:servings_bonus,:ingredient_reuse,:weekly_budget)
+	ON CONFLICT(user_id) DO UPDATE SET daily_kcal_min = excluded.daily_kcal_min, daily_kcal_max = excluded.daily_kcal_max,
+	weekly_kcal_min = excluded.weekly_kcal_min, weekly_kcal_max = excluded.weekly_kcal_max,
+	ingredient_spacing = excluded.ingredient_spacing, main_ingredients = excluded.main_ingredients,
+	hemisphere = excluded.hemisphere, holiday_country = excluded.holiday_country,
+	holiday_region = excluded.holiday_region, max_prep_time = excluded.max_prep_time,
+	default_prep_time = excluded.default_prep_time, default_servings = excluded.default_servings,
+	high_servings = excluded.high_servings, servings_bonus = excluded.servings_bonus,
+	ingredient_reuse = excluded.ingredient_reuse, weekly_budget = excluded.weekly_budget`
+)
+
+type SQLiteSettingsRepository struct {
+	db *database.Database
+}
+
+type DBSettingsI interface {
+	GetSettings(userId string) (settings models.UserSettings, err error)
+	SaveSettings(settings models.UserSettings) (err error)
+}
+
+func NewSQLiteSettingsRepository(db *database.Database) *SQLiteSettingsRepository {
+	return &SQLiteSettingsRepository{
+		db: db,
+	}
+}
+
+// GetSettings returns the default settings when the user has not saved any.
+func (r *SQLiteSettingsRepository) GetSettings(userId string) (settings models.UserSettings, err error) {
+	var settingsList []models.UserSettings
+	if err = r.db.Conn.Select(&settingsList, getSettings, userId); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(settingsList) == 0 {
+		return models.NewUserSettings(userId), nil
+	}
+	return settingsList[0], nil
+}
+
+func (r *SQLiteSettingsRepository) SaveSettings(settings models.UserSettings) (err error) {
+	if _, err = r.db.Conn.NamedExec(saveSettings, settings); err != nil {
+		log.Error(err)
+	}
+	return
+}
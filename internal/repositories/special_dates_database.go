@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getSpecialDates     = "SELECT * FROM special_dates WHERE user_id = ? ORDER BY id"
+	getSpecialDate      = "SELECT * FROM special_dates WHERE user_id = ? AND id = ?"
+	createSpecialDate   = "INSERT INTO special_dates (id,user_id,date,name,source) VALUES (?,?,?,?,?)"
+	deleteSpecialDate   = "DELETE FROM special_dates WHERE user_id = ? AND id = ?"
+	deleteSpecialSource = "DELETE FROM special_dates WHERE user_id = ? AND source = ?"
+)
+
+type SQLiteSpecialDateRepository struct {
+	db *database.Database
+}
+
+type DBSpecialDateI interface {
+	GetSpecialDates(userId string) (dates []models.SpecialDate, err error)
+	GetSpecialDate(userId, id string) (date models.SpecialDate, err error)
+	CreateSpecialDate(date models.SpecialDate) (err error)
+	DeleteSpecialDate(userId, id string) (err error)
+	ReplaceSpecialDates(userId, source string, dates []models.SpecialDate) (err error)
+}
+
+func NewSQLiteSpecialDateRepository(db *database.Database) *SQLiteSpecialDateRepository {
+	return &SQLiteSpecialDateRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteSpecialDateRepository) GetSpecialDates(userId string) (dates []models.SpecialDate, err error) {
+	dates = []models.SpecialDate{}
+	if err = r.db.Conn.Select(&dates, getSpecialDates, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteSpecialDateRepository) GetSpecialDate(userId, id string) (date models.SpecialDate, err error) {
+	var dates []models.SpecialDate
+	if err = r.db.Conn.Select(&dates, getSpecialDate, userId, id); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(dates) == 0 {
+		return models.SpecialDate{}, internal.ErrSpecialDateNotFound
+	}
+	return dates[0], nil
+}
+
+func (r *SQLiteSpecialDateRepository) CreateSpecialDate(date models.SpecialDate) (err error) {
+	if _, err = r.db.Conn.Exec(createSpecialDate, date.Id, date.UserId, date.Date, date.Name, date.Source); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteSpecialDateRepository) DeleteSpecialDate(userId, id string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteSpecialDate, userId, id); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+// ReplaceSpecialDates replaces, in one transaction, the special dates of the
+// user with the given source.
+func (r *SQLiteSpecialDateRepository) ReplaceSpecialDates(userId, source string, dates []models.SpecialDate) (err error) {
+	return runInTx(r.db, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.Exec(deleteSpecialSource, userId, source); err != nil {
+			return
+		}
+		for _, d := range dates {
+			if _, err = tx.Exec(createSpecialDate, d.Id, d.UserId, d.Date, d.Name, d.Source); err != nil {
+				return
+			}
+		}
+		return
+	})
+}
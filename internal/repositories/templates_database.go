@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getTemplates   = "SELECT * FROM templates WHERE user_id = ? ORDER BY name"
+	getTemplate    = "SELECT * FROM templates WHERE user_id = ? AND id = ?"
+	createTemplate = "INSERT INTO templates (id,user_id,name) VALUES (?,?,?)"
+	updateTemplate = "UPDATE templates SET name = ? WHERE user_id = ? AND id = ?"
+	deleteTemplate = "DELETE FROM templates WHERE user_id = ? AND id = ?"
+
+	getTemplateDays    = "SELECT * FROM template_days WHERE user_id = ? ORDER BY weekday"
+	createTemplateDay  = "INSERT INTO template_days (template_id,user_id,weekday,meal_id,meal_type) VALUES (?,?,?,?,?)"
+	deleteTemplateDays = "DELETE FROM template_days WHERE user_id = ? AND template_id = ?"
+)
+
+type SQLiteTemplateRepository struct {
+	db *database.Database
+}
+
+type DBTemplateI interface {
+	GetTemplates(userId string) (templates []models.Template, err error)
+	GetTemplate(userId, id string) (template models.Template, err error)
+	CreateTemplate(template models.Template) (err error)
+	UpdateTemplate(template models.Template) (err error)
+	DeleteTemplate(userId, id string) (err error)
+}
+
+func NewSQLiteTemplateRepository(db *database.Database) *SQLiteTemplateRepository {
+	return &SQLiteTemplateRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteTemplateRepository) GetTemplates(userId string) (templates []models.Template, err error) {
+	if err = r.db.Conn.Select(&templates, getTemplates, userId); err != nil {
+		log.Error(err)
+		return
+	}
+	var days []models.TemplateDay
+	if err = r.db.Conn.Select(&days, getTemplateDays, userId); err != nil {
+		log.Error(err)
+		return
+	}
+	for i := range templates {
+		templates[i].Days = []models.TemplateDay{}
+		for _, d := range days {
+			if d.TemplateId == templates[i].Id {
+				templates[i].Days = append(templates[i].Days, d)
+			}
+		}
+	}
+	return
+}
+
+func (r *SQLiteTemplateRepository) GetTemplate(userId, id string) (template models.Template, err error) {
+	var templates []models.Template
+	if templates, err = r.GetTemplates(userId); err != nil {
+		return
+	}
+	for _, t := range templates {
+		if t.Id == id {
+			return t, nil
+		}
+	}
+	return models.Template{}, internal.ErrTemplateNotFound
+}
+
+func (r *SQLiteTemplateRepository) CreateTemplate(template models.Template) (err error) {
+	return runInTx(r.db, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.Exec(createTemplate, template.Id, template.UserId, template.Name); err != nil {
+			return
+		}
+		return insertTemplateDays(tx, template)
+	})
+}
+
+func (r *SQLiteTemplateRepository) UpdateTemplate(template models.Template) (err error) {
+	return runInTx(r.db, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.Exec(updateTemplate, template.Name, template.UserId, template.Id); err != nil {
+			return
+		}
+		if _, err = tx.Exec(deleteTemplateDays, template.UserId, template.Id); err != nil {
+			return
+		}
+		return insertTemplateDays(tx, template)
+	})
+}
+
+func (r *SQLiteTemplateRepository) DeleteTemplate(userId, id string) (err error) {
+	return runInTx(r.db, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.Exec(deleteTemplateDays, userId, id); err != nil {
+			return
+		}
+		_, err = tx.Exec(deleteTemplate, userId, id)
+		return
+	})
+}
+
+func insertTemplateDays(tx *sqlx.Tx, template models.Template) (err error) {
+	for _, d := range template.Days {
+		if _, err = tx.Exec(createTemplateDay, template.Id, template.UserId, d.Weekday, d.MealId, d.MealType); err != nil {
+			return
+		}
+	}
+	return
+}
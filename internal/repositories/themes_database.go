@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getThemes   = "SELECT * FROM weekday_themes WHERE user_id = ? ORDER BY weekday, id"
+	getTheme    = "SELECT * FROM weekday_themes WHERE user_id = ? AND id = ?"
+	createTheme = "INSERT INTO weekday_themes (id,user_id,weekday,name,attribute,value,exclude,strict) VALUES (?,?,?,?,?,?,?,?)"
+	updateTheme = "UPDATE weekday_themes SET weekday = ?, name = ?, attribute = ?, value = ?, exclude = ?, strict = ? WHERE user_id = ? AND id = ?"
+	deleteTheme = "DELETE FROM weekday_themes WHERE user_id = ? AND id = ?"
+)
+
+type SQLiteThemeRepository struct {
+	db *database.Database
+}
+
+type DBThemeI interface {
+	GetThemes(userId string) (themes []models.WeekdayTheme, err error)
+	GetTheme(userId, id string) (theme models.WeekdayTheme, err error)
+	CreateTheme(theme models.WeekdayTheme) (err error)
+	UpdateTheme(theme models.WeekdayTheme) (err error)
+	DeleteTheme(userId, id string) (err error)
+}
+
+func NewSQLiteThemeRepository(db *database.Database) *SQLiteThemeRepository {
+	return &SQLiteThemeRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteThemeRepository) GetThemes(userId string) (themes []models.WeekdayTheme, err error) {
+	themes = []models.WeekdayTheme{}
+	if err = r.db.Conn.Select(&themes, getThemes, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteThemeRepository) GetTheme(userId, id string) (theme models.WeekdayTheme, err error) {
+	var themes []models.WeekdayTheme
+	if err = r.db.Conn.Select(&themes, getTheme, userId, id); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(themes) == 0 {
+		return models.WeekdayTheme{}, internal.ErrThemeNotFound
+	}
+	return themes[0], nil
+}
+
+func (r *SQLiteThemeRepository) CreateTheme(t models.WeekdayTheme) (err error) {
+	if _, err = r.db.Conn.Exec(createTheme, t.Id, t.UserId, t.Weekday, t.Name, t.Attribute, t.Value, t.Exclude, t.Strict); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteThemeRepository) UpdateTheme(t models.WeekdayTheme) (err error) {
+	if _, err = r.db.Conn.Exec(updateTheme, t.Weekday, t.Name, t.Attribute, t.Value, t.Exclude, t.Strict, t.UserId, t.Id); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteThemeRepository) DeleteTheme(userId, id string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteTheme, userId, id); err != nil {
+		log.Error(err)
+	}
+	return
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"calendar/pkg/database"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+)
+
+// runInTx executes fn inside a transaction, rolling it back if fn fails.
+func runInTx(db *database.Database, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := db.Conn.Beginx()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if err = fn(tx); err != nil {
+		log.Error(err)
+		_ = tx.Rollback()
+		return
+	}
+	if err = tx.Commit(); err != nil {
+		log.Error(err)
+	}
+	return
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"calendar/internal"
+	"calendar/internal/models"
+	"calendar/pkg/database"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	getTypePolicies = "SELECT * FROM type_policies WHERE user_id = ? ORDER BY type"
+	getTypePolicy   = "SELECT * FROM type_policies WHERE user_id = ? AND type = ?"
+	saveTypePolicy  = `INSERT INTO type_policies (user_id,type,frequency,min_spacing,weekdays,holidays,max_count,window_days)
+	VALUES (:user_id,:type,:frequency,:min_spacing,:weekdays,:holidays,:max_count,:window_days)
+	ON CONFLICT(user_id,type) DO UPDATE SET frequency = excluded.frequency, min_spacing = excluded.min_spacing,
+	weekdays = excluded.weekdays, holidays = excluded.holidays, max_count = excluded.max_count,
+	window_days = excluded.window_days`
+	deleteTypePolicy = "DELETE FROM type_policies WHERE user_id = ? AND type = ?"
+)
+
+type SQLiteTypePolicyRepository struct {
+	db *database.Database
+}
+
+type DBTypePolicyI interface {
+	GetTypePolicies(userId string) (policies []models.TypePolicy, err error)
+	GetTypePolicy(userId, mealType string) (policy models.TypePolicy, err error)
+	SaveTypePolicy(policy models.TypePolicy) (err error)
+	DeleteTypePolicy(userId, mealType string) (err error)
+}
+
+func NewSQLiteTypePolicyRepository(db *database.Database) *SQLiteTypePolicyRepository {
+	return &SQLiteTypePolicyRepository{
+		db: db,
+	}
+}
+
+func (r *SQLiteTypePolicyRepository) GetTypePolicies(userId string) (policies []models.TypePolicy, err error) {
+	policies = []models.TypePolicy{}
+	if err = r.db.Conn.Select(&policies, getTypePolicies, userId); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteTypePolicyRepository) GetTypePolicy(userId, mealType string) (policy models.TypePolicy, err error) {
+	var policies []models.TypePolicy
+	if err = r.db.Conn.Select(&policies, getTypePolicy, userId, mealType); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(policies) == 0 {
+		return models.TypePolicy{}, internal.ErrTypePolicyNotFound
+	}
+	return policies[0], nil
+}
+
+// SaveTypePolicy creates the policy of the type or replaces the existing one.
+func (r *SQLiteTypePolicyRepository) SaveTypePolicy(policy models.TypePolicy) (err error) {
+	if _, err = r.db.Conn.NamedExec(saveTypePolicy, policy); err != nil {
+		log.Error(err)
+	}
+	return
+}
+
+func (r *SQLiteTypePolicyRepository) DeleteTypePolicy(userId, mealType string) (err error) {
+	if _, err = r.db.Conn.Exec(deleteTypePolicy, userId, mealType); err != nil {
+		log.Error(err)
+	}
+	return
+}
@@ -11,8 +11,64 @@ const (
 	RouteCalendar         = "/user/:user_id/calendar"
 	RouteCalendarRedo     = "/user/:user_id/redo"
 	RouteCalendarRedoWeek = "/user/:user_id/redoweek"
+	RouteCalendarCopy     = "/user/:user_id/calendar/copy"
+	RouteCalendarImport   = "/user/:user_id/calendar/import"
+	RouteCalendarCSV      = "/user/:user_id/calendar/csv"
+	RouteCalendarPrint    = "/user/:user_id/calendar/print"
+	RouteTemplates        = "/user/:user_id/template"
+	RouteTemplate         = "/user/:user_id/template/:template_id"
+	RouteTemplateApply    = "/user/:user_id/template/:template_id/apply"
+	RouteRules            = "/user/:user_id/rule"
+	RouteRule             = "/user/:user_id/rule/:rule_id"
+	RouteThemes           = "/user/:user_id/theme"
+	RouteTheme            = "/user/:user_id/theme/:theme_id"
+	RouteSettings         = "/user/:user_id/settings"
+	RouteExclusions       = "/user/:user_id/exclusion"
+	RouteExclusion        = "/user/:user_id/exclusion/:exclusion_id"
+	RouteSpecialDates     = "/user/:user_id/special-date"
+	RouteSpecialDate      = "/user/:user_id/special-date/:special_date_id"
+	RouteSpecialDateICS   = "/user/:user_id/special-date/import"
+	RouteTypePolicies     = "/user/:user_id/type-policy"
+	RouteTypePolicy       = "/user/:user_id/type-policy/:type"
+	RouteMealYields       = "/user/:user_id/meal-yield"
+	RouteMealYield        = "/user/:user_id/meal-yield/:meal_id"
+	RouteShoppingList     = "/user/:user_id/shopping-list"
+	RoutePantry           = "/user/:user_id/pantry"
+	RoutePantryItem       = "/user/:user_id/pantry/:pantry_item_id"
+	RouteMealCosts        = "/user/:user_id/meal-cost"
+	RouteMealCost         = "/user/:user_id/meal-cost/:meal_id"
+	RouteIngredientPrices = "/user/:user_id/ingredient-price"
+	RouteIngredientPrice  = "/user/:user_id/ingredient-price/:ingredient_price_id"
+	RouteCalendarICS      = "/user/:user_id/calendar.ics"
+	RouteFeedToken        = "/user/:user_id/feed-token"
+	RouteFeed             = "/feeds/:token" // :token.ics, echo keeps the extension in the param
+	RouteCalDAV           = "/caldav/:user_id/"
+	RouteCalDAVEvent      = "/caldav/:user_id/:event" // :event is yyyymmdd.ics
 
-	ParamUserID = "user_id"
+	ParamUserID        = "user_id"
+	ParamTemplateID    = "template_id"
+	ParamRuleID        = "rule_id"
+	ParamThemeID       = "theme_id"
+	ParamExclusionID   = "exclusion_id"
+	ParamSpecialDateID = "special_date_id"
+	ParamMealType      = "type"
+	ParamMealID        = "meal_id"
+	ParamPantryItemID  = "pantry_item_id"
+	ParamPriceID       = "ingredient_price_id"
+	ParamFeedToken     = "token"
+	ParamEvent         = "event"
+
+	QuerySummary = "summary"
+	QueryFrom    = "from"
+	QueryTo      = "to"
+	QueryFormat  = "format"
+
+	QueryMealTime    = "time"
+	QueryDuration    = "duration"
+	QueryIngredients = "ingredients"
+	QueryImportMode  = "mode"
+	QueryWeeks       = "weeks"
+	QueryPrintList   = "list"
 )
 
 type ErrorResponse struct {
@@ -24,12 +80,34 @@ func (e *ErrorResponse) Error() string {
 }
 
 type ErrorBody struct {
-	Status  int    `json:"status"`
+	Status  int         `json:"status"`
+	Message string      `json:"message"`
+	Details []ItemError `json:"details,omitempty"`
+}
+
+// ItemError describes why a single element of a bulk request was rejected.
+type ItemError struct {
+	Index   int    `json:"index"`
+	Date    string `json:"date,omitempty"`
+	MealId  string `json:"meal_id,omitempty"`
 	Message string `json:"message"`
 }
 
+// ItemsError wraps one of the known errors with the per item errors that caused it.
+type ItemsError struct {
+	Err     error
+	Details []ItemError
+}
+
+func (e *ItemsError) Error() string {
+	return e.Err.Error()
+}
+
 func NewErrorResponse(c echo.Context, err error) error {
 	errResponse := &ErrorResponse{Err: errorsMap[err.Error()]}
+	if itemsErr, ok := err.(*ItemsError); ok {
+		errResponse.Err.Details = itemsErr.Details
+	}
 	if errResponse.Err.Status == 0 {
 		if err := c.JSON(http.StatusInternalServerError, err); err != nil {
 			return err
@@ -43,32 +121,106 @@ func NewErrorResponse(c echo.Context, err error) error {
 }
 
 var errorsMap = map[string]ErrorBody{
-	ErrUserIDNotPresent.Error():      {Status: http.StatusBadRequest, Message: ErrUserIDNotPresent.Error()},
-	ErrWrongBody.Error():             {Status: http.StatusBadRequest, Message: ErrWrongBody.Error()},
-	ErrInvalidDateFormat.Error():     {Status: http.StatusBadRequest, Message: ErrInvalidDateFormat.Error()},
-	ErrCalendarNotFound.Error():      {Status: http.StatusNotFound, Message: ErrCalendarNotFound.Error()},
-	ErrUserNotFound.Error():          {Status: http.StatusNotFound, Message: ErrUserNotFound.Error()},
-	ErrMealNotFound.Error():          {Status: http.StatusNotFound, Message: ErrMealNotFound.Error()},
-	ErrMealsNotFound.Error():         {Status: http.StatusNotFound, Message: ErrMealsNotFound.Error()},
-	ErrDateNotFound.Error():          {Status: http.StatusNotFound, Message: ErrDateNotFound.Error()},
-	ErrCalendarAlreadyExists.Error(): {Status: http.StatusConflict, Message: ErrCalendarAlreadyExists.Error()},
-	ErrSomethingWentWrong.Error():    {Status: http.StatusInternalServerError, Message: ErrSomethingWentWrong.Error()},
-	ErrReturningAllMeals.Error():     {Status: http.StatusInternalServerError, Message: ErrReturningAllMeals.Error()},
-	ErrReturningMeal.Error():         {Status: http.StatusInternalServerError, Message: ErrReturningMeal.Error()},
-	ErrReturningUser.Error():         {Status: http.StatusInternalServerError, Message: ErrReturningUser.Error()},
+	ErrUserIDNotPresent.Error():        {Status: http.StatusBadRequest, Message: ErrUserIDNotPresent.Error()},
+	ErrTemplateIDNotPresent.Error():    {Status: http.StatusBadRequest, Message: ErrTemplateIDNotPresent.Error()},
+	ErrRuleIDNotPresent.Error():        {Status: http.StatusBadRequest, Message: ErrRuleIDNotPresent.Error()},
+	ErrInvalidRule.Error():             {Status: http.StatusBadRequest, Message: ErrInvalidRule.Error()},
+	ErrThemeIDNotPresent.Error():       {Status: http.StatusBadRequest, Message: ErrThemeIDNotPresent.Error()},
+	ErrExclusionIDNotPresent.Error():   {Status: http.StatusBadRequest, Message: ErrExclusionIDNotPresent.Error()},
+	ErrExcludedIngredient.Error():      {Status: http.StatusBadRequest, Message: ErrExcludedIngredient.Error()},
+	ErrSpecialDateIDNotPresent.Error(): {Status: http.StatusBadRequest, Message: ErrSpecialDateIDNotPresent.Error()},
+	ErrInvalidSpecialDate.Error():      {Status: http.StatusBadRequest, Message: ErrInvalidSpecialDate.Error()},
+	ErrInvalidICS.Error():              {Status: http.StatusBadRequest, Message: ErrInvalidICS.Error()},
+	ErrUnknownHolidays.Error():         {Status: http.StatusBadRequest, Message: ErrUnknownHolidays.Error()},
+	ErrMealTypeNotPresent.Error():      {Status: http.StatusBadRequest, Message: ErrMealTypeNotPresent.Error()},
+	ErrMealIDNotPresent.Error():        {Status: http.StatusBadRequest, Message: ErrMealIDNotPresent.Error()},
+	ErrInvalidDateRange.Error():        {Status: http.StatusBadRequest, Message: ErrInvalidDateRange.Error()},
+	ErrUnsupportedFormat.Error():       {Status: http.StatusBadRequest, Message: ErrUnsupportedFormat.Error()},
+	ErrPantryItemIDNotPresent.Error():  {Status: http.StatusBadRequest, Message: ErrPantryItemIDNotPresent.Error()},
+	ErrPriceIDNotPresent.Error():       {Status: http.StatusBadRequest, Message: ErrPriceIDNotPresent.Error()},
+	ErrInvalidICSOptions.Error():       {Status: http.StatusBadRequest, Message: ErrInvalidICSOptions.Error()},
+	ErrInvalidImportMode.Error():       {Status: http.StatusBadRequest, Message: ErrInvalidImportMode.Error()},
+	ErrEventNotFound.Error():           {Status: http.StatusNotFound, Message: ErrEventNotFound.Error()},
+	ErrEventChanged.Error():            {Status: http.StatusPreconditionFailed, Message: ErrEventChanged.Error()},
+	ErrEventMealNotFound.Error():       {Status: http.StatusUnprocessableEntity, Message: ErrEventMealNotFound.Error()},
+	ErrInvalidCSV.Error():              {Status: http.StatusBadRequest, Message: ErrInvalidCSV.Error()},
+	ErrInvalidPrintOptions.Error():     {Status: http.StatusBadRequest, Message: ErrInvalidPrintOptions.Error()},
+	ErrWrongBody.Error():               {Status: http.StatusBadRequest, Message: ErrWrongBody.Error()},
+	ErrInvalidDateFormat.Error():       {Status: http.StatusBadRequest, Message: ErrInvalidDateFormat.Error()},
+	ErrInvalidCalendarDays.Error():     {Status: http.StatusBadRequest, Message: ErrInvalidCalendarDays.Error()},
+	ErrDuplicatedDate.Error():          {Status: http.StatusBadRequest, Message: ErrDuplicatedDate.Error()},
+	ErrCalendarNotFound.Error():        {Status: http.StatusNotFound, Message: ErrCalendarNotFound.Error()},
+	ErrUserNotFound.Error():            {Status: http.StatusNotFound, Message: ErrUserNotFound.Error()},
+	ErrMealNotFound.Error():            {Status: http.StatusNotFound, Message: ErrMealNotFound.Error()},
+	ErrMealsNotFound.Error():           {Status: http.StatusNotFound, Message: ErrMealsNotFound.Error()},
+	ErrDateNotFound.Error():            {Status: http.StatusNotFound, Message: ErrDateNotFound.Error()},
+	ErrTemplateNotFound.Error():        {Status: http.StatusNotFound, Message: ErrTemplateNotFound.Error()},
+	ErrRuleNotFound.Error():            {Status: http.StatusNotFound, Message: ErrRuleNotFound.Error()},
+	ErrThemeNotFound.Error():           {Status: http.StatusNotFound, Message: ErrThemeNotFound.Error()},
+	ErrExclusionNotFound.Error():       {Status: http.StatusNotFound, Message: ErrExclusionNotFound.Error()},
+	ErrSpecialDateNotFound.Error():     {Status: http.StatusNotFound, Message: ErrSpecialDateNotFound.Error()},
+	ErrTypePolicyNotFound.Error():      {Status: http.StatusNotFound, Message: ErrTypePolicyNotFound.Error()},
+	ErrMealYieldNotFound.Error():       {Status: http.StatusNotFound, Message: ErrMealYieldNotFound.Error()},
+	ErrPantryItemNotFound.Error():      {Status: http.StatusNotFound, Message: ErrPantryItemNotFound.Error()},
+	ErrMealCostNotFound.Error():        {Status: http.StatusNotFound, Message: ErrMealCostNotFound.Error()},
+	ErrPriceNotFound.Error():           {Status: http.StatusNotFound, Message: ErrPriceNotFound.Error()},
+	ErrFeedTokenNotFound.Error():       {Status: http.StatusNotFound, Message: ErrFeedTokenNotFound.Error()},
+	ErrCalendarAlreadyExists.Error():   {Status: http.StatusConflict, Message: ErrCalendarAlreadyExists.Error()},
+	ErrFeedTokenAlreadyExists.Error():  {Status: http.StatusConflict, Message: ErrFeedTokenAlreadyExists.Error()},
+	ErrSomethingWentWrong.Error():      {Status: http.StatusInternalServerError, Message: ErrSomethingWentWrong.Error()},
+	ErrReturningAllMeals.Error():       {Status: http.StatusInternalServerError, Message: ErrReturningAllMeals.Error()},
+	ErrReturningMeal.Error():           {Status: http.StatusInternalServerError, Message: ErrReturningMeal.Error()},
+	ErrReturningUser.Error():           {Status: http.StatusInternalServerError, Message: ErrReturningUser.Error()},
 }
 var (
-	ErrUserIDNotPresent      = errors.New("error con el ID del usuario dado")
-	ErrSomethingWentWrong    = errors.New("error inesperado")
-	ErrWrongBody             = errors.New("el cuerpo enviado es erróneo")
-	ErrCalendarNotFound      = errors.New("calendario no encontrado")
-	ErrCalendarAlreadyExists = errors.New("este usuario ya tiene un calendario")
-	ErrUserNotFound          = errors.New("usuario no encontrado")
-	ErrMealNotFound          = errors.New("comida no encontrada")
-	ErrMealsNotFound         = errors.New("no hay comidas registradas")
-	ErrReturningAllMeals     = errors.New("error inesperado recuperando las comidas")
-	ErrReturningMeal         = errors.New("error inesperado recuperando la información de la comida")
-	ErrReturningUser         = errors.New("error inesperado recuperando la información del usuario")
-	ErrDateNotFound          = errors.New("fecha indicada no encontrada en el calendario")
-	ErrInvalidDateFormat     = errors.New("formato inválido de fecha, debe ser aaaa/MM/dd")
+	ErrUserIDNotPresent        = errors.New("error con el ID del usuario dado")
+	ErrSomethingWentWrong      = errors.New("error inesperado")
+	ErrWrongBody               = errors.New("el cuerpo enviado es erróneo")
+	ErrCalendarNotFound        = errors.New("calendario no encontrado")
+	ErrCalendarAlreadyExists   = errors.New("este usuario ya tiene un calendario")
+	ErrUserNotFound            = errors.New("usuario no encontrado")
+	ErrMealNotFound            = errors.New("comida no encontrada")
+	ErrMealsNotFound           = errors.New("no hay comidas registradas")
+	ErrReturningAllMeals       = errors.New("error inesperado recuperando las comidas")
+	ErrReturningMeal           = errors.New("error inesperado recuperando la información de la comida")
+	ErrReturningUser           = errors.New("error inesperado recuperando la información del usuario")
+	ErrDateNotFound            = errors.New("fecha indicada no encontrada en el calendario")
+	ErrInvalidDateFormat       = errors.New("formato inválido de fecha, debe ser aaaa/MM/dd")
+	ErrInvalidCalendarDays     = errors.New("uno o varios días indicados no son válidos")
+	ErrDuplicatedDate          = errors.New("fecha repetida en la petición")
+	ErrTemplateIDNotPresent    = errors.New("error con el ID de la plantilla dado")
+	ErrTemplateNotFound        = errors.New("plantilla no encontrada")
+	ErrRuleIDNotPresent        = errors.New("error con el ID de la regla dado")
+	ErrRuleNotFound            = errors.New("regla no encontrada")
+	ErrInvalidRule             = errors.New("regla de repetición inválida")
+	ErrThemeIDNotPresent       = errors.New("error con el ID del tema dado")
+	ErrThemeNotFound           = errors.New("tema no encontrado")
+	ErrExclusionIDNotPresent   = errors.New("error con el ID de la exclusión dado")
+	ErrExclusionNotFound       = errors.New("exclusión no encontrada")
+	ErrExcludedIngredient      = errors.New("la comida contiene un ingrediente excluido")
+	ErrSpecialDateIDNotPresent = errors.New("error con el ID de la fecha especial dado")
+	ErrSpecialDateNotFound     = errors.New("fecha especial no encontrada")
+	ErrInvalidSpecialDate      = errors.New("formato inválido de fecha especial, debe ser MM/dd, aaaa/MM/dd o easter+N")
+	ErrInvalidICS              = errors.New("el fichero iCalendar enviado es erróneo")
+	ErrUnknownHolidays         = errors.New("país o región de festivos desconocido")
+	ErrMealTypeNotPresent      = errors.New("error con el tipo de comida dado")
+	ErrTypePolicyNotFound      = errors.New("política del tipo de comida no encontrada")
+	ErrMealIDNotPresent        = errors.New("error con el ID de la comida dado")
+	ErrMealYieldNotFound       = errors.New("raciones de la comida no encontradas")
+	ErrInvalidDateRange        = errors.New("la fecha de inicio es posterior a la de fin")
+	ErrUnsupportedFormat       = errors.New("formato de respuesta no soportado")
+	ErrPantryItemIDNotPresent  = errors.New("error con el ID del ingrediente de la despensa dado")
+	ErrPantryItemNotFound      = errors.New("ingrediente de la despensa no encontrado")
+	ErrMealCostNotFound        = errors.New("coste de la comida no encontrado")
+	ErrPriceIDNotPresent       = errors.New("error con el ID del precio del ingrediente dado")
+	ErrPriceNotFound           = errors.New("precio del ingrediente no encontrado")
+	ErrInvalidICSOptions       = errors.New("opciones de exportación iCalendar inválidas, la hora debe ser HH:mm")
+	ErrFeedTokenNotFound       = errors.New("token de suscripción no encontrado")
+	ErrFeedTokenAlreadyExists  = errors.New("este usuario ya tiene un token de suscripción")
+	ErrInvalidImportMode       = errors.New("modo de importación inválido, debe ser merge o replace")
+	ErrEventNotFound           = errors.New("evento no encontrado")
+	ErrEventChanged            = errors.New("el evento ha cambiado desde la última vez que se leyó")
+	ErrEventMealNotFound       = errors.New("ninguna comida coincide con el título del evento")
+	ErrInvalidCSV              = errors.New("el fichero CSV enviado es erróneo, debe tener las columnas date y meal_id")
+	ErrInvalidPrintOptions     = errors.New("opciones de impresión inválidas, weeks debe estar entre 1 y 8 y list ser ingredients o shopping")
 )
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// budgetExceeded is subtracted from the meals that cost more than what is
+	// left of the weekly budget.
+	budgetExceeded = 4.0
+	// budgetWeight scales the penalty of the relative excess of a meal over the
+	// share of the budget left for each of the remaining days of the week.
+	budgetWeight = 2.0
+)
+
+// MealCost returns the estimated cost of the meal: the one set by the user or,
+// when there is none, the sum of the prices of its ingredients. ok is false when
+// neither the meal nor any of its ingredients has a price.
+func (s *CalendarTools) MealCost(meal *models.MealToFront) (cost float64, ok bool) {
+	if cost, ok = s.preferences.Costs[meal.Id]; ok {
+		return
+	}
+	for _, i := range meal.Ingredients {
+		if c, priced := s.ingredientCost(i); priced {
+			cost += c
+			ok = true
+		}
+	}
+	return
+}
+
+// BudgetScore penalises the meal when it costs more than the share of the weekly
+// budget left for each remaining day of its week (monday to sunday), and more so
+// when it costs more than all that is left. As the budget is used up the share
+// gets smaller, steering the week away from expensive meals. Meals without cost
+// are not adjusted.
+func (s *CalendarTools) BudgetScore(calendar []models.Calendar, meal *models.MealToFront, date time.Time, mealsById map[string]*models.MealToFront) (res float64) {
+	budget := s.preferences.Settings.WeeklyBudget
+	if budget <= 0 {
+		return
+	}
+	cost, ok := s.MealCost(meal)
+	if !ok || cost <= 0 {
+		return
+	}
+	start := weekStart(date)
+	day := dayOf(date)
+	planned := map[string]bool{}
+	var spent float64
+	for _, c := range calendar {
+		d, err := time.Parse("2006/01/02", c.Date)
+		if err != nil || d.Equal(day) || !weekStart(d).Equal(start) {
+			continue
+		}
+		planned[c.Date] = true
+		spent += s.dayCost(c, mealsById)
+	}
+	remaining := 0
+	for i := 0; i < 7; i++ {
+		d := start.AddDate(0, 0, i)
+		if !d.Before(day) && !planned[d.Format("2006/01/02")] {
+			remaining++
+		}
+	}
+	left := budget - spent
+	if cost > left {
+		return -budgetExceeded
+	}
+	if share := left / float64(remaining); cost > share {
+		res -= math.Min((cost-share)/share*budgetWeight, budgetExceeded)
+	}
+	return
+}
+
+// dayCost returns the cost of the meal of the day of the calendar, zero for the
+// leftovers days and the meals without cost.
+func (s *CalendarTools) dayCost(day models.Calendar, mealsById map[string]*models.MealToFront) float64 {
+	if day.LeftoverOf != "" || day.MealId == "" {
+		return 0
+	}
+	if meal, ok := mealsById[day.MealId]; ok {
+		cost, _ := s.MealCost(meal)
+		return cost
+	}
+	return s.preferences.Costs[day.MealId]
+}
+
+// ingredientCost returns the cost of the ingredient with the price of the user
+// for it. The price is scaled to the quantity of the ingredient when both use
+// the same unit, otherwise the ingredient is taken to cost a whole price.
+func (s *CalendarTools) ingredientCost(ingredient string) (cost float64, ok bool) {
+	quantity, unit, name := parseIngredient(ingredient)
+	price, ok := s.priceOf(name)
+	if !ok {
+		return 0, false
+	}
+	priceQuantity, priceUnit := price.Quantity, strings.ToLower(strings.TrimSpace(price.Unit))
+	if priceQuantity <= 0 {
+		priceQuantity = 1
+	}
+	if u, known := ingredientUnits[priceUnit]; known {
+		priceQuantity *= u.factor
+		priceUnit = u.unit
+	}
+	if quantity == 0 || pieceUnit(unit) != pieceUnit(priceUnit) {
+		return price.Price, true
+	}
+	return price.Price * quantity / priceQuantity, true
+}
+
+// priceOf returns the price of the ingredient, which is the price of the longest
+// ingredient of the user it contains, as "pollo" for "pechuga de pollo".
+func (s *CalendarTools) priceOf(name string) (price models.IngredientPrice, ok bool) {
+	key := " " + ingredientKey(name) + " "
+	var longest int
+	for _, p := range s.preferences.Prices {
+		_, _, priceName := parseIngredient(p.Ingredient)
+		priceKey := ingredientKey(priceName)
+		if priceKey != "" && len(priceKey) > longest && strings.Contains(key, " "+priceKey+" ") {
+			price, ok, longest = p, true, len(priceKey)
+		}
+	}
+	return
+}
+
+// pieceUnit returns the unit with the pieces, counted without unit or as "ud",
+// as no unit.
+func pieceUnit(unit string) string {
+	if unit == "ud" {
+		return ""
+	}
+	return unit
+}
+
+// roundCost rounds the cost to cents.
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
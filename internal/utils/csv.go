@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"bytes"
+	"calendar/internal/models"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// calendarCSVColumns are the columns of the CSV of the calendar. Only date and
+// meal_id are read back.
+var calendarCSVColumns = []string{"date", "meal_id", "name", "type", "kcal"}
+
+var errCSVColumns = errors.New("the CSV has no date or meal_id column")
+
+// CalendarCSV returns the days of the calendar as CSV, with a header. meals are
+// the meals of the calendar by id, for their type.
+func CalendarCSV(calendar []models.Calendar, meals map[string]models.MealToFront) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write(calendarCSVColumns); err != nil {
+		return nil, err
+	}
+	for _, day := range calendar {
+		if err := w.Write([]string{day.Date, day.MealId, day.Name, meals[day.MealId].Type, strconv.Itoa(day.Kcal)}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+// ParseCalendarCSV reads the days of a CSV of the calendar. When the first row
+// is a header the columns are found by name, otherwise they are in the order of
+// the export. Spreadsheets saving with semicolons and a BOM are read as well.
+func ParseCalendarCSV(r io.Reader) (rows []models.CalendarCSVRow, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	dateColumn, mealColumn := 0, 1
+	for first := true; ; first = false {
+		record, errRead := reader.Read()
+		if errRead == io.EOF {
+			break
+		}
+		if errRead != nil {
+			return nil, errRead
+		}
+		if first && isCSVHeader(record) {
+			if dateColumn, mealColumn, err = csvColumns(record); err != nil {
+				return
+			}
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		row := models.CalendarCSVRow{Row: line}
+		if dateColumn < len(record) {
+			row.Date = strings.TrimSpace(record[dateColumn])
+		}
+		if mealColumn < len(record) {
+			row.MealId = strings.TrimSpace(record[mealColumn])
+		}
+		if row.Date == "" && row.MealId == "" {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return
+}
+
+// isCSVHeader tells whether the row names any of the columns of the CSV.
+func isCSVHeader(record []string) bool {
+	for _, name := range record {
+		for _, column := range calendarCSVColumns {
+			if strings.EqualFold(strings.TrimSpace(name), column) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func csvColumns(header []string) (dateColumn, mealColumn int, err error) {
+	dateColumn, mealColumn = -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "date":
+			dateColumn = i
+		case "meal_id":
+			mealColumn = i
+		}
+	}
+	if dateColumn < 0 || mealColumn < 0 {
+		return 0, 0, errCSVColumns
+	}
+	return
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"strings"
+	"time"
+)
+
+// ExcludedIngredient returns the first ingredient of the meal excluded on the
+// date (aaaa/MM/dd). Ingredients are compared by substring and case insensitive,
+// so "nuez" excludes "nuez moscada". An empty date only checks the permanent
+// exclusions.
+func ExcludedIngredient(meal *models.MealToFront, exclusions []models.IngredientExclusion, date string) (ingredient string, excluded bool) {
+	for _, e := range exclusions {
+		if !exclusionActive(e, date) {
+			continue
+		}
+		value := strings.ToLower(strings.TrimSpace(e.Ingredient))
+		for _, i := range meal.Ingredients {
+			if strings.Contains(strings.ToLower(i), value) {
+				return i, true
+			}
+		}
+	}
+	return "", false
+}
+
+// FilterExcludedMeals removes the meals containing a permanently excluded
+// ingredient.
+func FilterExcludedMeals(meals []*models.MealToFront, exclusions []models.IngredientExclusion) (filtered []*models.MealToFront) {
+	if len(exclusions) == 0 {
+		return meals
+	}
+	for _, m := range meals {
+		if _, excluded := ExcludedIngredient(m, exclusions, ""); !excluded {
+			filtered = append(filtered, m)
+		}
+	}
+	return
+}
+
+// allowedMeals returns the meals without any ingredient excluded on the date.
+// Unlike themes there is no fallback: when every meal is excluded the day is
+// left without a meal.
+func (s *CalendarTools) allowedMeals(meals []*models.MealToFront, date time.Time) (filtered []*models.MealToFront) {
+	if len(s.preferences.Exclusions) == 0 {
+		return meals
+	}
+	for _, m := range meals {
+		if _, excluded := ExcludedIngredient(m, s.preferences.Exclusions, date.Format("2006/01/02")); !excluded {
+			filtered = append(filtered, m)
+		}
+	}
+	return
+}
+
+// Excluded reports whether the meal has any ingredient excluded on the date.
+func (s *CalendarTools) Excluded(meal *models.MealToFront, date time.Time) bool {
+	_, excluded := ExcludedIngredient(meal, s.preferences.Exclusions, date.Format("2006/01/02"))
+	return excluded
+}
+
+func exclusionActive(e models.IngredientExclusion, date string) bool {
+	if date == "" {
+		return e.From == "" && e.Until == ""
+	}
+	return (e.From == "" || e.From <= date) && (e.Until == "" || date <= e.Until)
+}
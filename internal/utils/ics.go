@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"calendar/pkg/ical"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// icsProdID identifies the application in the exported calendars.
+	icsProdID = "-//AMCProject//Calendars//ES"
+	// icsDefaultDuration are the minutes of the meals exported at a meal time
+	// without duration.
+	icsDefaultDuration = 60
+	// leftoversSuffix is added to the summary of the leftovers days.
+	leftoversSuffix = " (sobras)"
+)
+
+// ICSOptionsKey identifies the options that give the same iCalendar: the meal
+// time as HH:mm, the duration only along with it and the default one when not
+// given, and whether the ingredients are listed.
+func ICSOptionsKey(options models.ICSOptions) string {
+	key := "ingredients=" + strconv.FormatBool(options.Ingredients)
+	mealTime, err := time.Parse("15:04", options.MealTime)
+	if options.MealTime == "" || err != nil {
+		return key
+	}
+	duration := options.Duration
+	if duration <= 0 {
+		duration = icsDefaultDuration
+	}
+	return key + "&time=" + mealTime.Format("15:04") + "&duration=" + strconv.Itoa(duration)
+}
+
+// CalendarICS returns the days of the calendar with a meal as the events of an
+// iCalendar, stamped at the given time. meals are the meals of the calendar by
+// id, used to list their ingredients when the options ask for it.
+func CalendarICS(userId string, calendar []models.Calendar, meals map[string]models.MealToFront, options models.ICSOptions, stamp time.Time) *ical.Component {
+	cal := &ical.Component{Name: "VCALENDAR", Properties: []ical.Property{
+		{Name: "VERSION", Value: "2.0"},
+		{Name: "PRODID", Value: icsProdID},
+		{Name: "CALSCALE", Value: "GREGORIAN"},
+		{Name: "METHOD", Value: "PUBLISH"},
+		ical.NewText("X-WR-CALNAME", "Comidas"),
+	}}
+	mealTime, _ := time.Parse("15:04", options.MealTime)
+	duration := options.Duration
+	if duration <= 0 {
+		duration = icsDefaultDuration
+	}
+	for _, day := range calendar {
+		date, err := time.Parse("2006/01/02", day.Date)
+		if err != nil || day.MealId == "" {
+			continue
+		}
+		event := &ical.Component{Name: "VEVENT", Properties: []ical.Property{
+			{Name: "UID", Value: EventUID(userId, day.Date)},
+			{Name: "DTSTAMP", Value: stamp.UTC().Format("20060102T150405Z")},
+		}}
+		if options.MealTime == "" {
+			event.Properties = append(event.Properties,
+				ical.Property{Name: "DTSTART", Params: map[string]string{"VALUE": "DATE"}, Value: date.Format("20060102")},
+				ical.Property{Name: "DTEND", Params: map[string]string{"VALUE": "DATE"}, Value: date.AddDate(0, 0, 1).Format("20060102")},
+				ical.Property{Name: "TRANSP", Value: "TRANSPARENT"},
+			)
+		} else {
+			// Floating times, the meal is at the same time wherever the user is.
+			start := date.Add(time.Duration(mealTime.Hour())*time.Hour + time.Duration(mealTime.Minute())*time.Minute)
+			event.Properties = append(event.Properties,
+				ical.Property{Name: "DTSTART", Value: start.Format("20060102T150405")},
+				ical.Property{Name: "DTEND", Value: start.Add(time.Duration(duration) * time.Minute).Format("20060102T150405")},
+			)
+		}
+		summary := day.Name
+		if day.LeftoverOf != "" {
+			summary += leftoversSuffix
+		}
+		event.Properties = append(event.Properties, ical.NewText("SUMMARY", summary))
+		meal, ok := meals[day.MealId]
+		if description := eventDescription(day, meal.Ingredients, ok && options.Ingredients); description != "" {
+			event.Properties = append(event.Properties, ical.NewText("DESCRIPTION", description))
+		}
+		cal.Components = append(cal.Components, event)
+	}
+	return cal
+}
+
+// EventUID returns the UID of the event of the day of the user. It is the same
+// in every export, so calendar apps update the event instead of adding another.
+func EventUID(userId, date string) string {
+	return strings.ReplaceAll(date, "/", "") + "-" + userId + "@calendars"
+}
+
+func eventDescription(day models.Calendar, ingredients []string, withIngredients bool) string {
+	var lines []string
+	if day.LeftoverOf != "" {
+		lines = append(lines, "Sobras del "+day.LeftoverOf)
+	}
+	if day.Servings > 0 {
+		lines = append(lines, "Raciones: "+strconv.Itoa(day.Servings))
+	}
+	if withIngredients && len(ingredients) > 0 {
+		lines = append(lines, "Ingredientes:")
+		for _, i := range ingredients {
+			lines = append(lines, "- "+i)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"calendar/pkg/ical"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatchThreshold is the similarity from which the summary of an event
+// matches the name of a meal when none has the same name.
+const fuzzyMatchThreshold = 0.75
+
+const (
+	reasonInvalidDate    = "fecha inválida"
+	reasonDuplicatedDate = "fecha repetida"
+	reasonOutOfCalendar  = "fecha fuera del calendario"
+	reasonNoMeal         = "ninguna comida coincide"
+	reasonExcluded       = "la comida contiene un ingrediente excluido"
+)
+
+// ImportEvents returns the days of the calendar of the events from the from date
+// to the to date (aaaa/MM/dd), with the meal of the user their summary matches,
+// and reports the events that cannot be imported. Leftovers days of an exported
+// calendar are linked back to the day their meal is cooked.
+func ImportEvents(userId string, events []*ical.Component, meals []*models.MealToFront, exclusions []models.IngredientExclusion, from, to string) (days []models.Calendar, report models.CalendarImport) {
+	report.Imported = []models.ImportedDay{}
+	report.Unmatched = []models.UnmatchedEvent{}
+	seen := map[string]bool{}
+	leftovers := map[string]bool{}
+	for _, event := range events {
+		name, leftover := EventSummary(event)
+		summaryProp, _ := event.Get("SUMMARY")
+		summary := strings.TrimSpace(summaryProp.Text())
+		start, ok := event.Get("DTSTART")
+		date, err := start.Date()
+		if !ok || err != nil {
+			report.Unmatched = append(report.Unmatched, models.UnmatchedEvent{Summary: summary, Reason: reasonInvalidDate})
+			continue
+		}
+		day := date.Format("2006/01/02")
+		if day < from || day > to {
+			report.Unmatched = append(report.Unmatched, models.UnmatchedEvent{Date: day, Summary: summary, Reason: reasonOutOfCalendar})
+			continue
+		}
+		if seen[day] {
+			report.Unmatched = append(report.Unmatched, models.UnmatchedEvent{Date: day, Summary: summary, Reason: reasonDuplicatedDate})
+			continue
+		}
+		meal, fuzzy := MatchMeal(name, meals)
+		if meal == nil {
+			report.Unmatched = append(report.Unmatched, models.UnmatchedEvent{Date: day, Summary: summary, Reason: reasonNoMeal})
+			continue
+		}
+		if _, excluded := ExcludedIngredient(meal, exclusions, day); excluded {
+			report.Unmatched = append(report.Unmatched, models.UnmatchedEvent{Date: day, Summary: summary, Reason: reasonExcluded})
+			continue
+		}
+		seen[day] = true
+		leftovers[day] = leftover
+		days = append(days, models.Calendar{UserId: userId, MealId: meal.Id, Name: meal.Name, Kcal: meal.Kcal, Date: day})
+		report.Imported = append(report.Imported, models.ImportedDay{Date: day, Summary: summary, MealId: meal.Id, Name: meal.Name, Fuzzy: fuzzy})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	sort.SliceStable(report.Imported, func(i, j int) bool { return report.Imported[i].Date < report.Imported[j].Date })
+	cooked := map[string]string{}
+	for i, day := range days {
+		if !leftovers[day.Date] {
+			cooked[day.MealId] = day.Date
+			continue
+		}
+		if date, ok := cooked[day.MealId]; ok {
+			days[i].LeftoverOf = date
+		}
+	}
+	return
+}
+
+// EventSummary returns the summary of the event without the suffix of the
+// exported leftovers days, and whether it had it.
+func EventSummary(event *ical.Component) (name string, leftover bool) {
+	summary, _ := event.Get("SUMMARY")
+	name = strings.TrimSpace(summary.Text())
+	return strings.TrimSuffix(name, leftoversSuffix), strings.HasSuffix(name, leftoversSuffix)
+}
+
+// MatchMeal returns the meal named as the summary, ignoring case, accents and
+// punctuation. When there is none it returns the meal with the most similar
+// name, if similar enough, and fuzzy is true.
+func MatchMeal(summary string, meals []*models.MealToFront) (meal *models.MealToFront, fuzzy bool) {
+	key := mealNameKey(summary)
+	if key == "" {
+		return nil, false
+	}
+	best := fuzzyMatchThreshold
+	for _, m := range meals {
+		mealKey := mealNameKey(m.Name)
+		if mealKey == key {
+			return m, false
+		}
+		if s := similarity(key, mealKey); s >= best {
+			meal, best = m, s
+		}
+	}
+	return meal, meal != nil
+}
+
+// mealNameKey lowers the name and removes its accents and punctuation.
+func mealNameKey(name string) string {
+	name = accents.Replace(strings.ToLower(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// similarity returns 1 minus the edit distance of the strings relative to the
+// longest one.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
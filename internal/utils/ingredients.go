@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"strings"
+	"time"
+)
+
+const (
+	// ingredientWindow is how many days around a date are checked for
+	// ingredients in common.
+	ingredientWindow = 3
+	// ingredientOverlapWeight is subtracted when every ingredient of a meal is
+	// also in the meal of the day before or after, divided by the distance.
+	ingredientOverlapWeight = 2.0
+	// mainIngredientPenalty is subtracted when a meal shares a main ingredient
+	// with a meal closer than the user's ingredient spacing.
+	mainIngredientPenalty = 6.0
+)
+
+// IngredientScore penalizes the meal for the ingredients it shares with the
+// meals planned on nearby days. mealsById is used to know the ingredients of the
+// planned meals; meals not in it are not taken into account.
+func (s *CalendarTools) IngredientScore(calendar []models.Calendar, meal *models.MealToFront, date time.Time, mealsById map[string]*models.MealToFront) (res float64) {
+	if len(meal.Ingredients) == 0 {
+		return
+	}
+	ingredients := normalizeIngredients(meal.Ingredients)
+	mains := s.mainIngredients(meal)
+	spacing := s.preferences.Settings.IngredientSpacing
+	window := ingredientWindow
+	if spacing > window {
+		window = spacing
+	}
+	for _, c := range calendar {
+		other, ok := mealsById[c.MealId]
+		if !ok || len(other.Ingredients) == 0 {
+			continue
+		}
+		d, err := time.Parse("2006/01/02", c.Date)
+		if err != nil {
+			continue
+		}
+		distance := absDays(date, d)
+		if distance == 0 || distance > window {
+			continue
+		}
+		if distance <= ingredientWindow {
+			shared := 0
+			otherIngredients := normalizeIngredients(other.Ingredients)
+			for i := range ingredients {
+				if otherIngredients[i] {
+					shared++
+				}
+			}
+			res -= ingredientOverlapWeight * float64(shared) / float64(len(ingredients)) / float64(distance)
+		}
+		if distance < spacing && sharesAny(mains, s.mainIngredients(other)) {
+			res -= mainIngredientPenalty
+		}
+	}
+	return
+}
+
+// mainIngredients returns the configured main ingredients found in the meal, or
+// its first ingredient when the user has not configured any.
+func (s *CalendarTools) mainIngredients(meal *models.MealToFront) (mains []string) {
+	configured := s.preferences.Settings.MainIngredients
+	if len(configured) == 0 {
+		if len(meal.Ingredients) > 0 {
+			mains = append(mains, normalizeIngredient(meal.Ingredients[0]))
+		}
+		return
+	}
+	for _, main := range configured {
+		main = normalizeIngredient(main)
+		for _, ingredient := range meal.Ingredients {
+			if strings.Contains(normalizeIngredient(ingredient), main) {
+				mains = append(mains, main)
+				break
+			}
+		}
+	}
+	return
+}
+
+func normalizeIngredient(ingredient string) string {
+	return strings.ToLower(strings.TrimSpace(ingredient))
+}
+
+func normalizeIngredients(ingredients []string) map[string]bool {
+	normalized := make(map[string]bool, len(ingredients))
+	for _, ingredient := range ingredients {
+		normalized[normalizeIngredient(ingredient)] = true
+	}
+	return normalized
+}
+
+func sharesAny(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// absDays returns the number of calendar days between the days of both dates.
+func absDays(a, b time.Time) int {
+	days := int(dayOf(a).Sub(dayOf(b)).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
+
+// dayOf returns the date at midnight UTC, so dates of the same day compare equal.
+func dayOf(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"math"
+	"time"
+)
+
+const (
+	// kcalOutOfRange is subtracted from meals outside the daily kcal range.
+	kcalOutOfRange = 4.0
+	// kcalWeight scales the penalty of the relative distance between the kcal of
+	// a meal and the daily target that keeps the week inside its range.
+	kcalWeight = 3.0
+)
+
+// KcalScore returns the adjustment of the score of the meal for the user's kcal
+// targets. Meals without kcal information are not adjusted.
+func (s *CalendarTools) KcalScore(calendar []models.Calendar, meal *models.MealToFront, date time.Time) (res float64) {
+	settings := s.preferences.Settings
+	if meal.Kcal <= 0 {
+		return
+	}
+	if (settings.DailyKcalMin > 0 && meal.Kcal < settings.DailyKcalMin) ||
+		(settings.DailyKcalMax > 0 && meal.Kcal > settings.DailyKcalMax) {
+		res -= kcalOutOfRange
+	}
+	if target := s.dailyKcalTarget(calendar, date); target > 0 {
+		res -= math.Abs(float64(meal.Kcal)-target) / target * kcalWeight
+	}
+	return
+}
+
+// dailyKcalTarget returns the kcal the day should have so its week ends at the
+// middle of the weekly range, given the days of the week already planned. Days
+// before the date that are not planned are left out of the week.
+func (s *CalendarTools) dailyKcalTarget(calendar []models.Calendar, date time.Time) (target float64) {
+	settings := s.preferences.Settings
+	daily := rangeTarget(settings.DailyKcalMin, settings.DailyKcalMax)
+	weekly := rangeTarget(settings.WeeklyKcalMin, settings.WeeklyKcalMax)
+	if weekly == 0 {
+		return daily
+	}
+	day := date.Format("2006/01/02")
+	start := weekStart(date)
+	planned := map[string]bool{}
+	var plannedKcal float64
+	for _, c := range calendar {
+		d, err := time.Parse("2006/01/02", c.Date)
+		if err != nil || c.Date == day || c.Kcal <= 0 || !weekStart(d).Equal(start) {
+			continue
+		}
+		planned[c.Date] = true
+		plannedKcal += float64(c.Kcal)
+	}
+	remaining := 0
+	today, _ := time.Parse("2006/01/02", day)
+	for i := 0; i < 7; i++ {
+		d := start.AddDate(0, 0, i)
+		if !d.Before(today) && !planned[d.Format("2006/01/02")] {
+			remaining++
+		}
+	}
+	target = (float64(len(planned)+remaining)*weekly/7 - plannedKcal) / float64(remaining)
+	if settings.DailyKcalMin > 0 && target < float64(settings.DailyKcalMin) {
+		target = float64(settings.DailyKcalMin)
+	}
+	if settings.DailyKcalMax > 0 && target > float64(settings.DailyKcalMax) {
+		target = float64(settings.DailyKcalMax)
+	}
+	if target < 1 {
+		target = 1
+	}
+	return
+}
+
+// WeekSummaries returns the kcal and cost summary of every week of the calendar.
+// meals are the meals of the calendar by id, used to cost them.
+func (s *CalendarTools) WeekSummaries(calendar []models.Calendar, meals map[string]*models.MealToFront) (weeks []models.WeekSummary) {
+	settings := s.preferences.Settings
+	var current *models.WeekSummary
+	for _, c := range calendar {
+		d, err := time.Parse("2006/01/02", c.Date)
+		if err != nil || c.MealId == "" {
+			continue
+		}
+		from := weekStart(d).Format("2006/01/02")
+		if current == nil || current.From != from {
+			weeks = append(weeks, models.WeekSummary{From: from, To: weekStart(d).AddDate(0, 0, 6).Format("2006/01/02")})
+			current = &weeks[len(weeks)-1]
+		}
+		current.Days++
+		current.Kcal += c.Kcal
+		current.Cost += s.dayCost(c, meals)
+	}
+	for i := range weeks {
+		week := &weeks[i]
+		week.KcalMin = settings.WeeklyKcalMin * week.Days / 7
+		week.KcalMax = settings.WeeklyKcalMax * week.Days / 7
+		if week.KcalMin > 0 && week.Kcal < week.KcalMin {
+			week.KcalDeviation = week.Kcal - week.KcalMin
+		}
+		if week.KcalMax > 0 && week.Kcal > week.KcalMax {
+			week.KcalDeviation = week.Kcal - week.KcalMax
+		}
+		week.Cost = roundCost(week.Cost)
+		week.Budget = roundCost(settings.WeeklyBudget * float64(week.Days) / 7)
+		if week.Budget > 0 && week.Cost > week.Budget {
+			week.BudgetDeviation = roundCost(week.Cost - week.Budget)
+		}
+	}
+	return
+}
+
+// rangeTarget returns the middle of the range, or its only limit when the other
+// one is not set.
+func rangeTarget(min, max int) float64 {
+	switch {
+	case min > 0 && max > 0:
+		return float64(min+max) / 2
+	case min > 0:
+		return float64(min)
+	}
+	return float64(max)
+}
+
+// weekStart returns the monday of the week of the date, at midnight.
+func weekStart(date time.Time) time.Time {
+	day := dayOf(date)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
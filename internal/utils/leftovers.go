@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"sort"
+	"time"
+)
+
+// leftoversOf returns the leftovers days of the meal cooked on the given day,
+// as set by the meal yield of the user. Meals without a yield have none.
+func (s *CalendarTools) leftoversOf(cooked models.Calendar) (days []models.Calendar) {
+	yield, ok := s.preferences.Yields[cooked.MealId]
+	if !ok || yield.Days < 2 {
+		return nil
+	}
+	date, err := time.Parse("2006/01/02", cooked.Date)
+	if err != nil {
+		return nil
+	}
+	offset := yield.Offset
+	if offset < 1 {
+		offset = 1
+	}
+	for i := 0; i < yield.Days-1; i++ {
+		days = append(days, models.Calendar{
+			UserId:     cooked.UserId,
+			MealId:     cooked.MealId,
+			Name:       cooked.Name,
+			Kcal:       cooked.Kcal,
+			Date:       date.AddDate(0, 0, offset+i).Format("2006/01/02"),
+			LeftoverOf: cooked.Date,
+		})
+	}
+	return
+}
+
+// scheduleLeftovers fixes the leftovers of the cooked day that fall up to the
+// until date on the days not fixed yet.
+func (s *CalendarTools) scheduleLeftovers(fixed map[string]models.Calendar, cooked models.Calendar, until string) {
+	for _, day := range s.leftoversOf(cooked) {
+		if day.Date > until {
+			return
+		}
+		if _, ok := fixed[day.Date]; !ok {
+			fixed[day.Date] = day
+		}
+	}
+}
+
+// LinkLeftovers replaces the given days of the calendar, planning the leftovers
+// of their new meals on the following days not given. It returns the updated
+// calendar and the dates of the leftovers whose cooked meal was replaced, which
+// have to be planned again with RefillDays.
+func (s *CalendarTools) LinkLeftovers(calendar []models.Calendar, days []models.Calendar) (updated []models.Calendar, orphans []string) {
+	updated = append([]models.Calendar{}, calendar...)
+	positions := make(map[string]int, len(updated))
+	for i, c := range updated {
+		positions[c.Date] = i
+	}
+	requested := make(map[string]bool, len(days))
+	for _, day := range days {
+		requested[day.Date] = true
+	}
+	replaced := map[string]bool{}
+	scheduled := map[string]bool{}
+	for _, day := range days {
+		pos, ok := positions[day.Date]
+		if !ok {
+			continue
+		}
+		day.LeftoverOf = ""
+		updated[pos] = day
+		replaced[day.Date] = true
+		for _, leftover := range s.leftoversOf(day) {
+			pos, ok := positions[leftover.Date]
+			if !ok || requested[leftover.Date] {
+				continue
+			}
+			leftover.Servings = updated[pos].Servings
+			updated[pos] = leftover
+			replaced[leftover.Date] = true
+			scheduled[leftover.Date] = true
+		}
+	}
+	for _, c := range updated {
+		if c.LeftoverOf != "" && replaced[c.LeftoverOf] && !scheduled[c.Date] {
+			orphans = append(orphans, c.Date)
+		}
+	}
+	sort.Strings(orphans)
+	return
+}
+
+// RefillDays chooses a new meal with ReturnRandomMeal for every given date of
+// the calendar.
+func (s *CalendarTools) RefillDays(userId string, calendar []models.Calendar, meals []*models.MealToFront, dates []string) []models.Calendar {
+	refill := make(map[string]bool, len(dates))
+	for _, date := range dates {
+		refill[date] = true
+	}
+	for i, c := range calendar {
+		if !refill[c.Date] {
+			continue
+		}
+		date, _ := time.Parse("2006/01/02", c.Date)
+		meal := s.ReturnRandomMeal(calendar, meals, date)
+		calendar[i] = models.Calendar{UserId: userId, MealId: meal.Id, Name: meal.Name, Kcal: meal.Kcal, Date: c.Date, Servings: c.Servings}
+	}
+	return calendar
+}
+
+// UnlinkLeftovers returns, without their link, the days of the calendar outside
+// the given dates that are leftovers of one of them. They are used once the
+// meals of those dates are overwritten.
+func (s *CalendarTools) UnlinkLeftovers(calendar []models.Calendar, dates []string) (unlinked []models.Calendar) {
+	overwritten := make(map[string]bool, len(dates))
+	for _, date := range dates {
+		overwritten[date] = true
+	}
+	for _, c := range calendar {
+		if c.LeftoverOf != "" && overwritten[c.LeftoverOf] && !overwritten[c.Date] {
+			c.LeftoverOf = ""
+			unlinked = append(unlinked, c)
+		}
+	}
+	return
+}
@@ -8,18 +8,32 @@ import (
 	"time"
 )
 
-type CalendarTools struct{}
+type CalendarTools struct {
+	preferences Preferences
+}
 
 type ICalendarTools interface {
+	WithPreferences(preferences Preferences) *CalendarTools
 	CalendarCreator(userId string, meals []*models.MealToFront) (calendar []models.Calendar, err error)
 	UpdateDaysInCalendar(d string, calendar []models.Calendar, meals []*models.MealToFront, dates models.UpdateWeekCalendar) (finalCalendar []models.Calendar, err error)
 	UpdateNewDays(userId string, calendar []models.Calendar, meals []*models.MealToFront, days int) (finalCalendar []models.Calendar, err error)
-	ReturnRandomMeal(calendar []models.Calendar, meals []*models.MealToFront, wd int) (meal models.MealToFront)
-	CalendarContains(calendar []models.Calendar, mealId string) (distance float64)
-	SpecialMeal(meal *models.MealToFront, numb float64, wd int) (res float64)
+	LinkLeftovers(calendar []models.Calendar, days []models.Calendar) (updated []models.Calendar, orphans []string)
+	UnlinkLeftovers(calendar []models.Calendar, dates []string) (unlinked []models.Calendar)
+	RefillDays(userId string, calendar []models.Calendar, meals []*models.MealToFront, dates []string) []models.Calendar
+	CheckRepeats(calendar []models.Calendar, dates []string) (warnings []models.RepeatWarning)
+	WeekSummaries(calendar []models.Calendar, meals map[string]*models.MealToFront) (weeks []models.WeekSummary)
+	FilterMealsByType(meals []*models.MealToFront, mealType string) (filtered []*models.MealToFront)
+	Excluded(meal *models.MealToFront, date time.Time) bool
+	MealCost(meal *models.MealToFront) (cost float64, ok bool)
+	ReturnRandomMeal(calendar []models.Calendar, meals []*models.MealToFront, date time.Time) (meal models.MealToFront)
+	CalendarContains(calendar []models.Calendar, mealId string, date time.Time) (contains bool, distance float64)
+	SpecialMeal(meal *models.MealToFront, numb float64, date time.Time, servings int) (res float64)
 	GetHighestMeal(keyMeal []float64) (index int)
 }
 
+// CalendarTools has to implement the whole ICalendarTools interface.
+var _ ICalendarTools = (*CalendarTools)(nil)
+
 func NewCalendarToolsManager() *CalendarTools {
 	return &CalendarTools{}
 }
@@ -33,16 +47,14 @@ func (s *CalendarTools) CalendarCreator(userId string, meals []*models.MealToFro
 	} else {
 		days = 21 + (7 - int(wd))
 	}
-	for i := 0; i <= days; i++ {
-		newDate := t.AddDate(0, 0, i)
-		meal := s.ReturnRandomMeal(calendar, meals, newDate)
-		cal := models.Calendar{
-			UserId: userId,
-			MealId: meal.Id,
-			Name:   meal.Name,
-			Date:   newDate.Format("2006/01/02"),
-		}
-		calendar = append(calendar, cal)
+	dates := make([]time.Time, days+1)
+	for i := range dates {
+		dates[i] = t.AddDate(0, 0, i)
+	}
+	fixed := s.fixedDays(userId, dates)
+	until := dates[len(dates)-1].Format("2006/01/02")
+	for _, newDate := range dates {
+		calendar = append(calendar, s.planDay(userId, calendar, meals, fixed, newDate, s.preferences.Settings.DefaultServings, until))
 	}
 
 	return
@@ -50,26 +62,28 @@ func (s *CalendarTools) CalendarCreator(userId string, meals []*models.MealToFro
 
 func (s *CalendarTools) UpdateDaysInCalendar(id string, calendar []models.Calendar, meals []*models.MealToFront, dates models.UpdateWeekCalendar) (finalCalendar []models.Calendar, err error) {
 	var inRange bool
+	var toUpdate []int
 	finalCalendar = calendar
 	for i, c := range finalCalendar {
 		if c.Date == dates.From {
 			inRange = true
 		}
-		if !inRange {
-			continue
-		}
-		updateDay, _ := time.Parse("2006/01/02", c.Date)
-		meal := s.ReturnRandomMeal(finalCalendar, meals, updateDay)
-		finalCalendar[i] = models.Calendar{
-			UserId: id,
-			MealId: meal.Id,
-			Name:   meal.Name,
-			Date:   c.Date,
+		// Leftovers after the range go along with the meal they come from.
+		if inRange || (c.Date > dates.To && c.LeftoverOf >= dates.From && c.LeftoverOf <= dates.To) {
+			toUpdate = append(toUpdate, i)
 		}
 		if c.Date == dates.To {
 			inRange = false
 		}
 	}
+	updateDays := make([]time.Time, len(toUpdate))
+	for j, i := range toUpdate {
+		updateDays[j], _ = time.Parse("2006/01/02", finalCalendar[i].Date)
+	}
+	fixed := s.fixedDays(id, updateDays)
+	for j, i := range toUpdate {
+		finalCalendar[i] = s.planDay(id, finalCalendar, meals, fixed, updateDays[j], finalCalendar[i].Servings, dates.To)
+	}
 	return
 }
 
@@ -79,23 +93,50 @@ func (s *CalendarTools) UpdateNewDays(userId string, calendar []models.Calendar,
 		finalCalendar = calendar[days:]
 	}
 	t, _ := time.Parse("2006/01/02", finalCalendar[len(finalCalendar)-1].Date)
-	for i := 0; i < days; i++ {
-		newDate := t.AddDate(0, 0, i+1)
-		meal := s.ReturnRandomMeal(finalCalendar, meals, newDate)
-		cal := models.Calendar{
-			UserId: userId,
-			MealId: meal.Id,
-			Name:   meal.Name,
-			Date:   newDate.Format("2006/01/02"),
-		}
-		finalCalendar = append(finalCalendar, cal)
+	newDates := make([]time.Time, days)
+	for i := range newDates {
+		newDates[i] = t.AddDate(0, 0, i+1)
+	}
+	fixed := s.fixedDays(userId, newDates)
+	until := t.AddDate(0, 0, days).Format("2006/01/02")
+	for _, newDate := range newDates {
+		finalCalendar = append(finalCalendar, s.planDay(userId, finalCalendar, meals, fixed, newDate, s.preferences.Settings.DefaultServings, until))
+	}
+	return
+}
 
+// planDay returns the day of the calendar on date with the given servings: the
+// fixed one when there is any, otherwise a meal chosen with ReturnRandomMeal,
+// whose leftovers are fixed on the following days up to until.
+func (s *CalendarTools) planDay(userId string, calendar []models.Calendar, meals []*models.MealToFront, fixed map[string]models.Calendar, date time.Time, servings int, until string) (day models.Calendar) {
+	if day, ok := fixed[date.Format("2006/01/02")]; ok {
+		day.Servings = servings
+		return day
 	}
+	meal := s.ReturnRandomMeal(withFixed(calendar, fixed), meals, date)
+	day = models.Calendar{
+		UserId:   userId,
+		MealId:   meal.Id,
+		Name:     meal.Name,
+		Kcal:     meal.Kcal,
+		Date:     date.Format("2006/01/02"),
+		Servings: servings,
+	}
+	s.scheduleLeftovers(fixed, day, until)
 	return
 }
 
 func (s *CalendarTools) ReturnRandomMeal(calendar []models.Calendar, meals []*models.MealToFront, date time.Time) (meal models.MealToFront) {
 	var keyMeal []float64
+	mealsById := make(map[string]*models.MealToFront, len(meals))
+	for _, m := range meals {
+		mealsById[m.Id] = m
+	}
+	if meals = s.allowedMeals(meals, date); len(meals) == 0 {
+		return models.MealToFront{Name: models.NoMeal}
+	}
+	meals, themes := s.themedMeals(s.seasonMeals(meals, date), date)
+	servings := s.servingsOn(calendar, date)
 	for _, m := range meals {
 		numb := math.Abs(rand.Float64() * 3)
 		contains, distance := s.CalendarContains(calendar, m.Id, date)
@@ -111,14 +152,14 @@ func (s *CalendarTools) ReturnRandomMeal(calendar []models.Calendar, meals []*mo
 		if distance == 0 && contains {
 			numb = numb - 20
 		}
-		numb = s.SpecialMeal(m, numb, int(date.Weekday()))
-		if strings.EqualFold(m.Type, models.Semanal) && (distance >= 7 || distance == 0) {
-			if distance == 0 {
-				numb += 1.2
-			} else {
-				numb += 1.6 - (1/(distance))*2.3
-			}
-		}
+		numb = s.SpecialMeal(m, numb, date, servings)
+		numb += s.ThemeScore(m, themes)
+		numb += s.KcalScore(calendar, m, date)
+		numb += s.IngredientScore(calendar, m, date, mealsById)
+		numb += s.TypeScore(calendar, m, date, mealsById)
+		numb += s.PantryScore(m, date)
+		numb += s.ReuseScore(calendar, m, date, mealsById)
+		numb += s.BudgetScore(calendar, m, date, mealsById)
 		keyMeal = append(keyMeal, numb)
 	}
 	index := s.GetHighestMeal(keyMeal)
@@ -129,6 +170,10 @@ func (s *CalendarTools) ReturnRandomMeal(calendar []models.Calendar, meals []*mo
 func (s *CalendarTools) CalendarContains(calendar []models.Calendar, mealId string, date time.Time) (contains bool, distance float64) {
 	distance = 100
 	for _, c := range calendar {
+		// Leftovers are planned along with their meal, not repeats of it.
+		if c.LeftoverOf != "" {
+			continue
+		}
 		if c.MealId == mealId {
 			contains = true
 			compareDate, _ := time.Parse("2006/01/02", c.Date)
@@ -144,13 +189,59 @@ func (s *CalendarTools) CalendarContains(calendar []models.Calendar, mealId stri
 	return
 }
 
-func (s *CalendarTools) SpecialMeal(meal *models.MealToFront, numb float64, wd int) (res float64) {
-	res = numb
-	if strings.EqualFold(meal.Type, models.Ocasional) && (wd == 0 || wd == 6) {
-		res += 2.10
+// CheckRepeats returns a warning for every given date whose meal is also planned
+// the day before or the day after.
+func (s *CalendarTools) CheckRepeats(calendar []models.Calendar, dates []string) (warnings []models.RepeatWarning) {
+	for _, date := range dates {
+		var day models.Calendar
+		others := make([]models.Calendar, 0, len(calendar))
+		for _, c := range calendar {
+			if c.Date == date {
+				day = c
+				continue
+			}
+			others = append(others, c)
+		}
+		if day.MealId == "" {
+			continue
+		}
+		dayDate, _ := time.Parse("2006/01/02", date)
+		if contains, distance := s.CalendarContains(others, day.MealId, dayDate); contains && distance <= 1 {
+			warnings = append(warnings, models.RepeatWarning{Date: date, MealId: day.MealId, Name: day.Name, Distance: distance})
+		}
 	}
-	if strings.EqualFold(meal.Type, models.Ocasional) && (wd > 0 && wd < 6) {
-		res -= 2.9
+	return
+}
+
+// FilterMealsByType returns the meals of the given type, or all of them when no
+// type is given.
+func (s *CalendarTools) FilterMealsByType(meals []*models.MealToFront, mealType string) (filtered []*models.MealToFront) {
+	if mealType == "" {
+		return meals
+	}
+	for _, m := range meals {
+		if strings.EqualFold(m.Type, mealType) {
+			filtered = append(filtered, m)
+		}
+	}
+	return
+}
+
+// SpecialMeal favours the meals whose type policy has weekdays on those days,
+// and on holidays when the policy says so, and penalises them the rest of the
+// days. By default occasional meals go to weekends and holidays. Meals longer to
+// prepare than the budget of the weekday are penalised too, and occasional meals
+// are favoured on days with more servings than usual.
+func (s *CalendarTools) SpecialMeal(meal *models.MealToFront, numb float64, date time.Time, servings int) (res float64) {
+	res = numb - s.PrepTimePenalty(meal, date) + s.ServingsBonus(meal, servings)
+	policy, ok := s.typePolicy(meal.Type)
+	if !ok || len(policy.Weekdays) == 0 {
+		return res
+	}
+	if s.favouredDay(policy, date) {
+		res += favouredDayBonus
+	} else {
+		res -= unfavouredDayPenalty
 	}
 	return res
 }
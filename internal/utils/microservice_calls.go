@@ -7,23 +7,28 @@ import (
 	"encoding/json"
 	"github.com/labstack/gommon/log"
 	"net/http"
-	"time"
 )
 
 type Endpoints struct {
 }
 type EndpointsI interface {
-	GetAllMeals(userId string) (meals []*models.MealToFront, err error)
+	GetAllMeals(userId string, seasons []string) (meals []*models.MealToFront, err error)
 	GetMeal(userId, mealId string) (meal models.MealToFront, err error)
 }
 
 var httpClient = &http.Client{}
 
-func (e *Endpoints) GetAllMeals(userId string) (meals []*models.MealToFront, err error) {
+// GetAllMeals returns the meals of the user for any of the given seasons, or
+// all of them when no season is given.
+func (e *Endpoints) GetAllMeals(userId string, seasons []string) (meals []*models.MealToFront, err error) {
 	url := config.Config.MealsURL + "user/" + userId + "/meal"
-	season := getSeason()
-	if season != "" {
-		url += "?season[]=" + season
+	for i, season := range seasons {
+		if i == 0 {
+			url += "?"
+		} else {
+			url += "&"
+		}
+		url += "season[]=" + season
 	}
 	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -31,6 +36,11 @@ func (e *Endpoints) GetAllMeals(userId string) (meals []*models.MealToFront, err
 		return []*models.MealToFront{}, internal.ErrReturningAllMeals
 	}
 	response, err := httpClient.Do(request)
+	if err != nil {
+		log.Error(err)
+		return []*models.MealToFront{}, internal.ErrReturningAllMeals
+	}
+	defer response.Body.Close()
 	if response.StatusCode == 404 {
 		return []*models.MealToFront{}, nil
 	}
@@ -54,6 +64,11 @@ func (e *Endpoints) GetMeal(userId, mealId string) (meal models.MealToFront, err
 		return models.MealToFront{}, internal.ErrReturningMeal
 	}
 	response, err := httpClient.Do(request)
+	if err != nil {
+		log.Error(err)
+		return models.MealToFront{}, internal.ErrReturningMeal
+	}
+	defer response.Body.Close()
 	if response.StatusCode > 299 {
 		newError := new(internal.ErrorResponse)
 		err = json.NewDecoder(response.Body).Decode(&newError)
@@ -67,18 +82,3 @@ func (e *Endpoints) GetMeal(userId, mealId string) (meal models.MealToFront, err
 
 	return
 }
-
-func getSeason() string {
-	t := time.Now()
-	switch t.Month() {
-	case time.January, time.February, time.March:
-		return "invierno"
-	case time.April, time.May, time.June:
-		return "primavera"
-	case time.July, time.August, time.September:
-		return "verano"
-	case time.October, time.November, time.December:
-		return "otoño"
-	}
-	return ""
-}
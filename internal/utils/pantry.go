@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// pantryBonus is added for every pantry item used by the meal.
+	pantryBonus = 0.6
+	// pantryExpiryBonus is added on top for the items expiring today, decreasing
+	// to zero for the ones expiring pantryExpiryWindow days from now.
+	pantryExpiryBonus  = 1.8
+	pantryExpiryWindow = 7
+	// pantryMaxBonus caps the bonus of a meal.
+	pantryMaxBonus = 3.0
+)
+
+// PantryScore favours the meals using the items of the user's pantry, the more
+// the sooner they expire. Items are not taken into account after their expiry
+// date.
+func (s *CalendarTools) PantryScore(meal *models.MealToFront, date time.Time) (res float64) {
+	if len(s.preferences.Pantry) == 0 || len(meal.Ingredients) == 0 {
+		return
+	}
+	ingredients := make([]string, 0, len(meal.Ingredients))
+	for _, i := range meal.Ingredients {
+		_, _, name := parseIngredient(i)
+		ingredients = append(ingredients, " "+ingredientKey(name)+" ")
+	}
+	today := dayOf(time.Now())
+	for _, item := range s.preferences.Pantry {
+		var expiry time.Time
+		if item.Expiry != "" {
+			var err error
+			if expiry, err = time.Parse("2006/01/02", item.Expiry); err != nil || dayOf(date).After(expiry) {
+				continue
+			}
+		}
+		if !usesPantryItem(ingredients, item) {
+			continue
+		}
+		res += pantryBonus
+		if !expiry.IsZero() {
+			left := math.Max(expiry.Sub(today).Hours()/24, 0)
+			res += pantryExpiryBonus * math.Max(1-left/pantryExpiryWindow, 0)
+		}
+	}
+	return math.Min(res, pantryMaxBonus)
+}
+
+// usesPantryItem tells whether any of the ingredients, already normalized and
+// surrounded by spaces, is the item or contains it, as "pechuga de pollo" and
+// "pollo".
+func usesPantryItem(ingredients []string, item models.PantryItem) bool {
+	_, _, name := parseIngredient(item.Ingredient)
+	key := " " + ingredientKey(name) + " "
+	for _, i := range ingredients {
+		if strings.Contains(i, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"strings"
+	"time"
+)
+
+const (
+	// favouredDayBonus and unfavouredDayPenalty adjust the meals of a type with
+	// weekdays on the days it is favoured and the rest of them.
+	favouredDayBonus     = 2.10
+	unfavouredDayPenalty = 2.9
+	// unplannedBonus favours the meals with a minimum spacing never planned.
+	unplannedBonus = 1.2
+	// spacedBonus favours the meals with a minimum spacing planned far enough,
+	// decreasing with spacingDecay divided by the distance.
+	spacedBonus  = 1.6
+	spacingDecay = 2.3
+	// frequencyWeight adjusts the meals of a type with a frequency towards the
+	// target number per week.
+	frequencyWeight = 1.5
+	// maxCountPenalty is subtracted when a meal would exceed the maximum count
+	// of its type, as much as planning the same meal two days in a row.
+	maxCountPenalty = 20.0
+	// defaultPolicyWindow is the window of the maximum count when none is given.
+	defaultPolicyWindow = 7
+)
+
+// typePolicy returns the policy of the type of meal. The default policies are
+// used when the tools have no preferences.
+func (s *CalendarTools) typePolicy(mealType string) (policy models.TypePolicy, ok bool) {
+	policies := s.preferences.TypePolicies
+	if policies == nil {
+		policies = models.DefaultTypePolicies()
+	}
+	for _, p := range policies {
+		if strings.EqualFold(p.Type, mealType) {
+			return p, true
+		}
+	}
+	return models.TypePolicy{}, false
+}
+
+// favouredDay reports whether the policy favours the date.
+func (s *CalendarTools) favouredDay(policy models.TypePolicy, date time.Time) bool {
+	for _, wd := range policy.Weekdays {
+		if time.Weekday(wd) == date.Weekday() {
+			return true
+		}
+	}
+	if policy.Holidays {
+		_, ok := s.preferences.Holidays.Find(date)
+		return ok
+	}
+	return false
+}
+
+// TypeScore returns the adjustment of the score of the meal for the spacing,
+// frequency and maximum count of the policy of its type. mealsById is used to
+// know the type of the planned meals.
+func (s *CalendarTools) TypeScore(calendar []models.Calendar, meal *models.MealToFront, date time.Time, mealsById map[string]*models.MealToFront) (res float64) {
+	policy, ok := s.typePolicy(meal.Type)
+	if !ok {
+		return
+	}
+	if policy.MinSpacing > 0 {
+		res += s.spacingScore(calendar, meal.Id, date, policy.MinSpacing)
+	}
+	if policy.Frequency > 0 {
+		planned := countType(calendar, mealsById, meal.Type, weekStart(date), weekStart(date).AddDate(0, 0, 6), date)
+		if planned < policy.Frequency {
+			res += frequencyWeight
+		} else {
+			res -= frequencyWeight * float64(planned-policy.Frequency+1)
+		}
+	}
+	if policy.MaxCount > 0 && exceedsMaxCount(calendar, mealsById, meal.Type, date, policy) {
+		res -= maxCountPenalty
+	}
+	return
+}
+
+// spacingScore favours the meal when it has never been planned or when it is
+// planned minSpacing days away or more. Closer meals get no bonus, the repeats
+// are already penalised by CalendarContains.
+func (s *CalendarTools) spacingScore(calendar []models.Calendar, mealId string, date time.Time, minSpacing int) float64 {
+	_, distance := s.CalendarContains(calendar, mealId, date)
+	switch {
+	case distance == 0:
+		return unplannedBonus
+	case distance >= float64(minSpacing):
+		return spacedBonus - (1/distance)*spacingDecay
+	default:
+		return 0
+	}
+}
+
+// exceedsMaxCount reports whether planning a meal of the type on the date leaves
+// any window of the policy with more meals of the type than its maximum.
+func exceedsMaxCount(calendar []models.Calendar, mealsById map[string]*models.MealToFront, mealType string, date time.Time, policy models.TypePolicy) bool {
+	window := policy.Window
+	if window <= 0 {
+		window = defaultPolicyWindow
+	}
+	for start := dayOf(date).AddDate(0, 0, -(window - 1)); !start.After(dayOf(date)); start = start.AddDate(0, 0, 1) {
+		if countType(calendar, mealsById, mealType, start, start.AddDate(0, 0, window-1), date)+1 > policy.MaxCount {
+			return true
+		}
+	}
+	return false
+}
+
+// countType counts the meals of the type planned from the from date to the to
+// date, both included, leaving out the skip date.
+func countType(calendar []models.Calendar, mealsById map[string]*models.MealToFront, mealType string, from, to, skip time.Time) (count int) {
+	from, to, skip = dayOf(from), dayOf(to), dayOf(skip)
+	for _, c := range calendar {
+		m, ok := mealsById[c.MealId]
+		if !ok || !strings.EqualFold(m.Type, mealType) {
+			continue
+		}
+		d, err := time.Parse("2006/01/02", c.Date)
+		if err != nil || d.Equal(skip) || d.Before(from) || d.After(to) {
+			continue
+		}
+		count++
+	}
+	return
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"calendar/pkg/holidays"
+	"calendar/pkg/rrule"
+	"time"
+)
+
+// Preferences gathers the per user settings taken into account when the
+// calendar is generated.
+type Preferences struct {
+	Rules      []MealRule
+	Themes     []models.WeekdayTheme
+	Settings   models.UserSettings
+	Exclusions []models.IngredientExclusion
+	// Holidays are the public holidays and special dates of the user.
+	Holidays holidays.List
+	// TypePolicies decide how each type of meal is planned. When nil the default
+	// policies are used.
+	TypePolicies []models.TypePolicy
+	// Yields are the meal yields of the user by meal.
+	Yields map[string]models.MealYield
+	// Pantry are the ingredients the user has at home.
+	Pantry []models.PantryItem
+	// Costs are the meal costs set by the user by meal, and Prices the user's
+	// ingredient prices, which cost the rest of the meals.
+	Costs  map[string]float64
+	Prices []models.IngredientPrice
+}
+
+// MealRule is a recurring rule of the user with its meal already resolved.
+type MealRule struct {
+	Rule *rrule.Rule
+	Meal models.MealToFront
+}
+
+// WithPreferences returns a copy of the tools that generates calendars for a user
+// with the given preferences.
+func (s *CalendarTools) WithPreferences(preferences Preferences) *CalendarTools {
+	tools := *s
+	tools.preferences = preferences
+	return &tools
+}
+
+// fixedDays returns, by date, the days fixed by the recurring rules of the user.
+// When several rules match the same day the first one wins. Rules whose meal is
+// excluded on the date are ignored.
+func (s *CalendarTools) fixedDays(userId string, dates []time.Time) (fixed map[string]models.Calendar) {
+	fixed = map[string]models.Calendar{}
+	for _, date := range dates {
+		for _, r := range s.preferences.Rules {
+			if _, excluded := ExcludedIngredient(&r.Meal, s.preferences.Exclusions, date.Format("2006/01/02")); excluded {
+				continue
+			}
+			if r.Rule.Matches(date) {
+				fixed[date.Format("2006/01/02")] = models.Calendar{
+					UserId: userId,
+					MealId: r.Meal.Id,
+					Name:   r.Meal.Name,
+					Kcal:   r.Meal.Kcal,
+					Date:   date.Format("2006/01/02"),
+				}
+				break
+			}
+		}
+	}
+	return
+}
+
+// withFixed returns the calendar with its days replaced by the fixed ones, plus
+// the fixed days it does not contain yet, so the scoring of a day takes into
+// account the meals already fixed after it.
+func withFixed(calendar []models.Calendar, fixed map[string]models.Calendar) []models.Calendar {
+	if len(fixed) == 0 {
+		return calendar
+	}
+	dates := make(map[string]bool, len(calendar))
+	result := append([]models.Calendar{}, calendar...)
+	for i, c := range result {
+		dates[c.Date] = true
+		if day, ok := fixed[c.Date]; ok {
+			result[i] = day
+		}
+	}
+	for date, c := range fixed {
+		if !dates[date] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
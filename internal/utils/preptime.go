@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"math"
+	"time"
+)
+
+const (
+	// prepTimeOverBudget is subtracted from the meals that take longer than the
+	// budget of the day.
+	prepTimeOverBudget = 2.0
+	// prepTimeWeight scales the penalty of the time over the budget, relative to
+	// the budget and up to twice it.
+	prepTimeWeight = 2.0
+)
+
+// PrepTimePenalty returns how much the meal is penalised for taking longer to
+// prepare than the user's budget of the weekday. Meals without preparation time
+// are considered to take the user's default time. Holidays and special dates
+// have no budget.
+func (s *CalendarTools) PrepTimePenalty(meal *models.MealToFront, date time.Time) float64 {
+	budget := s.prepTimeBudget(date)
+	if budget <= 0 {
+		return 0
+	}
+	prepTime := meal.PrepTime
+	if prepTime <= 0 {
+		prepTime = s.preferences.Settings.DefaultPrepTime
+	}
+	if prepTime <= budget {
+		return 0
+	}
+	over := math.Min(float64(prepTime-budget)/float64(budget), 2)
+	return prepTimeOverBudget + prepTimeWeight*over
+}
+
+func (s *CalendarTools) prepTimeBudget(date time.Time) int {
+	budgets := s.preferences.Settings.MaxPrepTime
+	if len(budgets) != 7 {
+		return 0
+	}
+	if _, ok := s.preferences.Holidays.Find(date); ok {
+		return 0
+	}
+	return budgets[date.Weekday()]
+}
@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"bytes"
+	"calendar/internal/models"
+	"calendar/pkg/pdf"
+	_ "embed"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// printMargin, in points, is the margin of the pages of the printed plan.
+	printMargin = 36.0
+	// printCellHeight is the minimum height of the days of the printed plan.
+	printCellHeight = 60.0
+)
+
+var printWeekdays = [7]string{"Lunes", "Martes", "Miércoles", "Jueves", "Viernes", "Sábado", "Domingo"}
+
+//go:embed templates/print.html
+var printHTML string
+
+var printTemplate = template.Must(template.New("print").Funcs(template.FuncMap{
+	"shoppingItem": shoppingItemText,
+	"join":         strings.Join,
+}).Parse(printHTML))
+
+// PrintPlanOf lays out the given number of weeks of the calendar, starting with
+// the week of today.
+func PrintPlanOf(calendar []models.Calendar, today time.Time, weeks int) (plan models.PrintPlan) {
+	from := weekStart(today)
+	to := from.AddDate(0, 0, 7*weeks-1)
+	plan.From, plan.To = from.Format("02/01/2006"), to.Format("02/01/2006")
+	days := make(map[string]models.Calendar, len(calendar))
+	for _, day := range calendar {
+		days[day.Date] = day
+	}
+	for w := 0; w < weeks; w++ {
+		start := from.AddDate(0, 0, 7*w)
+		week := models.PrintWeek{Start: start.Format("2006/01/02")}
+		for d := 0; d < 7; d++ {
+			date := start.AddDate(0, 0, d)
+			printDay := models.PrintDay{Date: date.Format("2006/01/02"), Weekday: printWeekdays[d], Day: date.Format("02/01")}
+			if day, ok := days[printDay.Date]; ok && day.MealId != "" {
+				printDay.Meal, printDay.Leftover, printDay.Servings = day.Name, day.LeftoverOf != "", day.Servings
+			}
+			week.Days = append(week.Days, printDay)
+		}
+		plan.Weeks = append(plan.Weeks, week)
+	}
+	return
+}
+
+// PrintMealsOf returns the meals of the days with their ingredients, in the
+// order they are first cooked. meals are the meals of the days by id, the ones
+// missing are marked so.
+func PrintMealsOf(days []models.Calendar, meals map[string]models.MealToFront) (printMeals []models.PrintMeal) {
+	listed := map[string]bool{}
+	for _, day := range days {
+		if day.LeftoverOf != "" || listed[day.MealId] {
+			continue
+		}
+		listed[day.MealId] = true
+		meal, ok := meals[day.MealId]
+		printMeals = append(printMeals, models.PrintMeal{Name: day.Name, Ingredients: meal.Ingredients, Missing: !ok})
+	}
+	return
+}
+
+// PrintHTML renders the plan as a printable HTML page.
+func PrintHTML(plan models.PrintPlan) ([]byte, error) {
+	var b bytes.Buffer
+	if err := printTemplate.Execute(&b, plan); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// PrintPDF renders the plan as a landscape A4 PDF, a row of days per week and
+// as many weeks per page as fit. The lists start on a page of their own.
+func PrintPDF(plan models.PrintPlan) ([]byte, error) {
+	w := &printWriter{doc: pdf.New(pdf.A4Height, pdf.A4Width)}
+	w.newPage()
+	w.text("Menú del "+plan.From+" al "+plan.To, 16, true, 0)
+	w.y += 8
+	for _, week := range plan.Weeks {
+		w.week(week)
+	}
+	if len(plan.Meals) > 0 {
+		w.newPage()
+		w.text("Ingredientes", 16, true, 0)
+		for _, meal := range plan.Meals {
+			w.y += 6
+			w.text(meal.Name, 12, true, 0)
+			if meal.Missing {
+				w.text("Ingredientes no disponibles", 10, false, 10)
+			}
+			for _, ingredient := range meal.Ingredients {
+				w.text("- "+ingredient, 10, false, 10)
+			}
+		}
+	}
+	if plan.Shopping != nil {
+		w.newPage()
+		w.text("Lista de la compra", 16, true, 0)
+		for _, category := range plan.Shopping.Categories {
+			w.y += 6
+			w.text(category.Name, 12, true, 0)
+			for _, item := range category.Items {
+				w.text("- "+shoppingItemText(item), 10, false, 10)
+			}
+		}
+		if len(plan.Shopping.Missing) > 0 {
+			w.y += 6
+			w.text("Sin ingredientes: "+strings.Join(plan.Shopping.Missing, ", "), 10, false, 0)
+		}
+	}
+	var b bytes.Buffer
+	if err := w.doc.Encode(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// printWriter writes the plan from the top of the pages down, y being the top
+// of what is written next.
+type printWriter struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64
+}
+
+func (w *printWriter) newPage() {
+	w.page = w.doc.AddPage()
+	w.y = printMargin
+}
+
+func (w *printWriter) width() float64 {
+	return pdf.A4Height - 2*printMargin
+}
+
+// text writes the text wrapped to the width of the page, on the next page when
+// it does not fit.
+func (w *printWriter) text(text string, size float64, bold bool, indent float64) {
+	for _, line := range pdf.Wrap(text, size, bold, w.width()-indent) {
+		if w.y+size*1.4 > pdf.A4Width-printMargin {
+			w.newPage()
+		}
+		w.page.Text(printMargin+indent, w.y+size, size, bold, line)
+		w.y += size * 1.4
+	}
+}
+
+// week writes the week as a row of cells under a header with the days.
+func (w *printWriter) week(week models.PrintWeek) {
+	const header = 16.0
+	column := w.width() / 7
+	cells := make([][]printLine, len(week.Days))
+	height := printCellHeight
+	for i, day := range week.Days {
+		cells[i] = dayLines(day, column-10)
+		if h := linesHeight(cells[i]) + 14; h > height {
+			height = h
+		}
+	}
+	if w.y+header+height > pdf.A4Width-printMargin {
+		w.newPage()
+	}
+	w.page.Rect(printMargin, w.y, w.width(), header, 0.9)
+	for i, day := range week.Days {
+		x := printMargin + float64(i)*column
+		w.page.Text(x+5, w.y+11.5, 9, true, day.Weekday+" "+day.Day)
+		y := w.y + header + 5
+		for _, line := range cells[i] {
+			w.page.Text(x+5, y+line.size, line.size, false, line.text)
+			y += line.size * 1.3
+		}
+	}
+	for _, y := range []float64{w.y, w.y + header, w.y + header + height} {
+		w.page.Line(printMargin, y, printMargin+w.width(), y, 0.5)
+	}
+	for i := 0; i <= len(week.Days); i++ {
+		x := printMargin + float64(i)*column
+		w.page.Line(x, w.y, x, w.y+header+height, 0.5)
+	}
+	w.y += header + height + 12
+}
+
+type printLine struct {
+	text string
+	size float64
+}
+
+// dayLines returns the lines of the cell of the day: its meal wrapped to the
+// width of the cell, whether it is leftovers and its servings.
+func dayLines(day models.PrintDay, width float64) (lines []printLine) {
+	if day.Meal == "" {
+		return []printLine{{text: "Sin comida", size: 8}}
+	}
+	for _, line := range pdf.Wrap(day.Meal, 10, false, width) {
+		lines = append(lines, printLine{text: line, size: 10})
+	}
+	if day.Leftover {
+		lines = append(lines, printLine{text: strings.TrimSpace(leftoversSuffix), size: 8})
+	}
+	if day.Servings > 0 {
+		lines = append(lines, printLine{text: strconv.Itoa(day.Servings) + " raciones", size: 8})
+	}
+	return
+}
+
+func linesHeight(lines []printLine) (height float64) {
+	for _, line := range lines {
+		height += line.size * 1.3
+	}
+	return
+}
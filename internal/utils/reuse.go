@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"time"
+)
+
+// staplesCategory groups the ingredients bought seldom, such as oil or rice,
+// which are not rewarded for being reused.
+const staplesCategory = "Despensa"
+
+// ReuseScore rewards the meal for the ingredients it shares with the other meals
+// planned in its week (monday to sunday), so fewer ingredients are bought for
+// the week. It is weighed by the user's IngredientReuse setting, zero disables
+// it, against the penalties of IngredientScore for the nearby days.
+func (s *CalendarTools) ReuseScore(calendar []models.Calendar, meal *models.MealToFront, date time.Time, mealsById map[string]*models.MealToFront) (res float64) {
+	weight := s.preferences.Settings.IngredientReuse
+	if weight == 0 {
+		return
+	}
+	ingredients := reusableIngredients(meal)
+	if len(ingredients) == 0 {
+		return
+	}
+	start := weekStart(date)
+	end := start.AddDate(0, 0, 7)
+	day := dayOf(date)
+	week := map[string]bool{}
+	for _, c := range calendar {
+		other, ok := mealsById[c.MealId]
+		if !ok || c.LeftoverOf != "" {
+			continue
+		}
+		d, err := time.Parse("2006/01/02", c.Date)
+		if err != nil || d.Before(start) || !d.Before(end) || d.Equal(day) {
+			continue
+		}
+		for i := range reusableIngredients(other) {
+			week[i] = true
+		}
+	}
+	shared := 0
+	for i := range ingredients {
+		if week[i] {
+			shared++
+		}
+	}
+	return weight * float64(shared) / float64(len(ingredients))
+}
+
+// reusableIngredients returns the normalized ingredients of the meal that are
+// not staples.
+func reusableIngredients(meal *models.MealToFront) map[string]bool {
+	ingredients := make(map[string]bool, len(meal.Ingredients))
+	for _, i := range meal.Ingredients {
+		_, _, name := parseIngredient(i)
+		if name == "" || ingredientCategory(name) == staplesCategory {
+			continue
+		}
+		ingredients[ingredientKey(name)] = true
+	}
+	return ingredients
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"calendar/internal/config"
+	"calendar/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSeasons is the season mapping used when the SEASONS configuration is
+// not set.
+const defaultSeasons = "invierno:1,2,3;primavera:4,5,6;verano:7,8,9;otoño:10,11,12"
+
+// SeasonOf returns the season of the date in the given hemisphere. The mapping
+// of the configuration is the northern one, the southern hemisphere is six
+// months ahead. Months missing in the mapping have no season.
+func SeasonOf(date time.Time, hemisphere string) string {
+	month := date.Month()
+	if hemisphere == models.South {
+		month = (month+5)%12 + 1
+	}
+	return seasonsByMonth()[month]
+}
+
+// SeasonsBetween returns, in order and without repeats, the seasons of the days
+// from the from date to the to date, both included.
+func SeasonsBetween(from, to time.Time, hemisphere string) (seasons []string) {
+	seen := map[string]bool{}
+	for date := dayOf(from); !date.After(dayOf(to)); date = date.AddDate(0, 0, 1) {
+		season := SeasonOf(date, hemisphere)
+		if season == "" || seen[season] {
+			continue
+		}
+		seen[season] = true
+		seasons = append(seasons, season)
+	}
+	return
+}
+
+// seasonMeals returns the meals of the season of the date, plus the ones without
+// seasons or marked as general. When no meal is left all the meals are returned,
+// as they were already fetched for the seasons of the calendar.
+func (s *CalendarTools) seasonMeals(meals []*models.MealToFront, date time.Time) []*models.MealToFront {
+	season := SeasonOf(date, s.preferences.Settings.Hemisphere)
+	if season == "" {
+		return meals
+	}
+	var filtered []*models.MealToFront
+	for _, m := range meals {
+		if inSeason(m, season) {
+			filtered = append(filtered, m)
+		}
+	}
+	if len(filtered) == 0 {
+		return meals
+	}
+	return filtered
+}
+
+func inSeason(meal *models.MealToFront, season string) bool {
+	if len(meal.Seasons) == 0 {
+		return true
+	}
+	for _, s := range meal.Seasons {
+		if strings.EqualFold(s, season) || strings.EqualFold(s, models.General) {
+			return true
+		}
+	}
+	return false
+}
+
+// seasonsByMonth parses the SEASONS configuration, "season:month,month;...".
+// Malformed entries are ignored.
+func seasonsByMonth() map[time.Month]string {
+	mapping := config.Config.Seasons
+	if mapping == "" {
+		mapping = defaultSeasons
+	}
+	seasons := map[time.Month]string{}
+	for _, entry := range strings.Split(mapping, ";") {
+		season, months, ok := strings.Cut(entry, ":")
+		season = strings.TrimSpace(season)
+		if !ok || season == "" {
+			continue
+		}
+		for _, m := range strings.Split(months, ",") {
+			month, err := strconv.Atoi(strings.TrimSpace(m))
+			if err != nil || month < 1 || month > 12 {
+				continue
+			}
+			seasons[time.Month(month)] = season
+		}
+	}
+	return seasons
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"strings"
+	"time"
+)
+
+// ServingsBonus returns the bonus of the occasional meals on days with more
+// servings than usual, as set by the user.
+func (s *CalendarTools) ServingsBonus(meal *models.MealToFront, servings int) float64 {
+	if !strings.EqualFold(meal.Type, models.Ocasional) || !s.highServings(servings) {
+		return 0
+	}
+	return s.preferences.Settings.ServingsBonus
+}
+
+func (s *CalendarTools) highServings(servings int) bool {
+	settings := s.preferences.Settings
+	if settings.HighServings > 0 {
+		return servings >= settings.HighServings
+	}
+	return settings.DefaultServings > 0 && servings > settings.DefaultServings
+}
+
+// servingsOn returns the servings of the date in the calendar, or the default
+// ones when the date is not planned yet.
+func (s *CalendarTools) servingsOn(calendar []models.Calendar, date time.Time) int {
+	day := date.Format("2006/01/02")
+	for _, c := range calendar {
+		if c.Date == day {
+			return c.Servings
+		}
+	}
+	return s.preferences.Settings.DefaultServings
+}
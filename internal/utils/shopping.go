@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// otherCategory groups the ingredients of no known category.
+const otherCategory = "Otros"
+
+// shoppingCategories are the categories of the shopping list, in order. An
+// ingredient belongs to the first category with one of its words, the entries
+// of several words being looked for first so "tomate frito" is not a "tomate".
+var shoppingCategories = []struct {
+	name  string
+	words []string
+}{
+	{"Frutas y verduras", []string{"tomate", "lechuga", "cebolla", "ajo", "patata", "zanahoria", "pimiento", "calabacin",
+		"berenjena", "espinaca", "brocoli", "coliflor", "pepino", "puerro", "champiñon", "seta", "calabaza", "col",
+		"judia", "guisante", "alcachofa", "apio", "perejil", "cilantro", "albahaca", "manzana", "platano", "naranja",
+		"limon", "fresa", "pera", "uva", "melon", "sandia", "aguacate", "piña", "mango"}},
+	{"Carne", []string{"pollo", "pechuga", "ternera", "cerdo", "cordero", "pavo", "carne", "jamon", "chorizo", "bacon",
+		"panceta", "salchicha", "lomo", "costilla", "hamburguesa", "morcilla"}},
+	{"Pescado y marisco", []string{"pescado", "merluza", "salmon", "atun", "bacalao", "gamba", "langostino", "mejillon",
+		"almeja", "calamar", "sepia", "pulpo", "sardina", "boqueron", "dorada", "lubina", "rape"}},
+	{"Lácteos y huevos", []string{"leche", "queso", "yogur", "nata", "mantequilla", "huevo"}},
+	{"Panadería", []string{"pan", "baguette", "masa", "hojaldre", "harina"}},
+	{"Despensa", []string{"arroz", "pasta", "espagueti", "macarron", "fideo", "lenteja", "garbanzo", "alubia", "quinoa",
+		"cuscus", "aceite", "sal", "azucar", "vinagre", "pimienta", "pimenton", "comino", "oregano", "caldo", "salsa",
+		"tomate frito", "especia", "avena"}},
+}
+
+// ingredientUnits are the units understood in the ingredients, with the unit
+// they are added up as and its factor.
+var ingredientUnits = map[string]struct {
+	unit   string
+	factor float64
+}{
+	"g": {"g", 1}, "gr": {"g", 1}, "grs": {"g", 1}, "gramo": {"g", 1}, "gramos": {"g", 1},
+	"kg": {"g", 1000}, "kilo": {"g", 1000}, "kilos": {"g", 1000},
+	"ml": {"ml", 1}, "cl": {"ml", 10}, "l": {"ml", 1000}, "litro": {"ml", 1000}, "litros": {"ml", 1000},
+	"ud": {"ud", 1}, "uds": {"ud", 1}, "unidad": {"ud", 1}, "unidades": {"ud", 1},
+	"cucharada": {"cucharada", 1}, "cucharadas": {"cucharada", 1},
+	"cucharadita": {"cucharadita", 1}, "cucharaditas": {"cucharadita", 1},
+	"taza": {"taza", 1}, "tazas": {"taza", 1},
+	"lata": {"lata", 1}, "latas": {"lata", 1},
+	"diente": {"diente", 1}, "dientes": {"diente", 1},
+	"pizca": {"pizca", 1}, "pizcas": {"pizca", 1},
+}
+
+var accents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u")
+
+// ShoppingListOf returns the shopping list of the days of the calendar between
+// from and to (aaaa/MM/dd, both included). meals are the planned meals by id,
+// the days whose meal is not in it are reported as missing.
+func ShoppingListOf(calendar []models.Calendar, meals map[string]models.MealToFront, from, to string) (list models.ShoppingList) {
+	list = models.ShoppingList{From: from, To: to, Categories: []models.ShoppingCategory{}}
+	items := map[string]*models.ShoppingItem{}
+	var keys []string
+	missing := map[string]bool{}
+	for _, day := range calendar {
+		if day.Date < from || day.Date > to || day.MealId == "" || day.LeftoverOf != "" {
+			continue
+		}
+		meal, ok := meals[day.MealId]
+		if !ok {
+			if !missing[day.Name] {
+				missing[day.Name] = true
+				list.Missing = append(list.Missing, day.Name)
+			}
+			continue
+		}
+		seen := map[string]bool{}
+		for _, ingredient := range meal.Ingredients {
+			quantity, unit, name := parseIngredient(ingredient)
+			if name == "" {
+				continue
+			}
+			key := ingredientKey(name) + "|" + unit
+			item, ok := items[key]
+			if !ok {
+				item = &models.ShoppingItem{Name: name, Unit: unit, Meals: []string{}}
+				items[key] = item
+				keys = append(keys, key)
+			}
+			item.Quantity += quantity
+			if !seen[key] {
+				seen[key] = true
+				item.Count++
+				if !containsString(item.Meals, meal.Name) {
+					item.Meals = append(item.Meals, meal.Name)
+				}
+			}
+		}
+	}
+
+	byCategory := map[string][]models.ShoppingItem{}
+	sort.Strings(keys)
+	for _, key := range keys {
+		item := items[key]
+		category := ingredientCategory(item.Name)
+		byCategory[category] = append(byCategory[category], *item)
+	}
+	for _, category := range shoppingCategories {
+		if len(byCategory[category.name]) > 0 {
+			list.Categories = append(list.Categories, models.ShoppingCategory{Name: category.name, Items: byCategory[category.name]})
+		}
+	}
+	if len(byCategory[otherCategory]) > 0 {
+		list.Categories = append(list.Categories, models.ShoppingCategory{Name: otherCategory, Items: byCategory[otherCategory]})
+	}
+	return
+}
+
+// ShoppingListMarkdown renders the shopping list as a Markdown checklist, which
+// also reads well as plain text.
+func ShoppingListMarkdown(list models.ShoppingList) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Lista de la compra\n\n%s - %s\n", list.From, list.To)
+	for _, category := range list.Categories {
+		fmt.Fprintf(&b, "\n## %s\n\n", category.Name)
+		for _, item := range category.Items {
+			fmt.Fprintf(&b, "- [ ] %s\n", shoppingItemText(item))
+		}
+	}
+	if len(list.Missing) > 0 {
+		fmt.Fprintf(&b, "\nSin ingredientes: %s\n", strings.Join(list.Missing, ", "))
+	}
+	return b.String()
+}
+
+// shoppingItemText returns the item as a line of the list, with its quantity
+// when known and the meals that use it.
+func shoppingItemText(item models.ShoppingItem) string {
+	var b strings.Builder
+	if item.Quantity > 0 {
+		b.WriteString(strconv.FormatFloat(item.Quantity, 'f', -1, 64) + " ")
+		if item.Unit != "" {
+			b.WriteString(item.Unit + " ")
+		}
+	}
+	fmt.Fprintf(&b, "%s (%s)", item.Name, strings.Join(item.Meals, ", "))
+	return b.String()
+}
+
+// parseIngredient splits an ingredient such as "200 g de arroz", "1,5kg patatas"
+// or "2 huevos" into its quantity, unit and name. Ingredients without quantity
+// are returned as the name.
+func parseIngredient(ingredient string) (quantity float64, unit, name string) {
+	name = strings.Join(strings.Fields(normalizeIngredient(ingredient)), " ")
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return 0, "", name
+	}
+	number, glued := splitNumber(fields[0])
+	quantity, ok := parseQuantity(number)
+	if !ok {
+		return 0, "", name
+	}
+	rest := fields[1:]
+	if glued == "" && len(rest) > 1 {
+		if _, isUnit := ingredientUnits[rest[0]]; isUnit {
+			glued, rest = rest[0], rest[1:]
+		}
+	}
+	if glued != "" {
+		u, isUnit := ingredientUnits[glued]
+		if !isUnit {
+			return 0, "", name
+		}
+		quantity *= u.factor
+		unit = u.unit
+	}
+	if len(rest) > 1 && rest[0] == "de" {
+		rest = rest[1:]
+	}
+	return quantity, unit, strings.Join(rest, " ")
+}
+
+// splitNumber splits a leading number from the unit glued to it, as in "200g".
+func splitNumber(field string) (number, unit string) {
+	i := strings.IndexFunc(field, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != ',' && r != '/'
+	})
+	if i < 0 {
+		return field, ""
+	}
+	return field[:i], field[i:]
+}
+
+func parseQuantity(number string) (float64, bool) {
+	if number == "" {
+		return 0, false
+	}
+	if n, d, ok := strings.Cut(number, "/"); ok {
+		num, errN := strconv.ParseFloat(n, 64)
+		den, errD := strconv.ParseFloat(d, 64)
+		if errN != nil || errD != nil || den == 0 {
+			return 0, false
+		}
+		return num / den, true
+	}
+	quantity, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+	return quantity, err == nil && quantity > 0
+}
+
+// ingredientKey normalizes the name of an ingredient so that "Tomates" and
+// "tomate" or "calabacín" and "calabacines" are the same ingredient.
+func ingredientKey(name string) string {
+	words := strings.Fields(accents.Replace(normalizeIngredient(name)))
+	for i, w := range words {
+		words[i] = singular(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// singular removes the usual Spanish plural endings of a word.
+func singular(word string) string {
+	if len(word) <= 3 || !strings.HasSuffix(word, "s") {
+		return word
+	}
+	if strings.HasSuffix(word, "es") && strings.ContainsAny(word[len(word)-3:len(word)-2], "lnrdj") {
+		return word[:len(word)-2]
+	}
+	if strings.ContainsAny(word[len(word)-2:len(word)-1], "aeiou") {
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func ingredientCategory(name string) string {
+	key := " " + ingredientKey(name) + " "
+	for _, phrases := range []bool{true, false} {
+		for _, category := range shoppingCategories {
+			for _, word := range category.words {
+				if strings.Contains(word, " ") == phrases && strings.Contains(key, " "+word+" ") {
+					return category.name
+				}
+			}
+		}
+	}
+	return otherCategory
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"calendar/internal/models"
+	"strings"
+	"time"
+)
+
+// themeBonus is added to the score of a meal for every non strict theme of the
+// day it fulfils. It is higher than the random part of the score so themed
+// meals win unless they were repeated recently.
+const themeBonus = 3.5
+
+// themedMeals returns the meals that fulfil every strict theme of the date, and
+// the themes that must be scored as a bonus. When no meal fulfils the strict
+// themes all the meals are returned and the strict themes are scored as a bonus
+// too, so the day is still planned as close to the theme as possible.
+func (s *CalendarTools) themedMeals(meals []*models.MealToFront, date time.Time) (filtered []*models.MealToFront, bonusThemes []models.WeekdayTheme) {
+	var strictThemes []models.WeekdayTheme
+	for _, theme := range s.preferences.Themes {
+		if theme.Weekday != int(date.Weekday()) {
+			continue
+		}
+		if theme.Strict {
+			strictThemes = append(strictThemes, theme)
+		} else {
+			bonusThemes = append(bonusThemes, theme)
+		}
+	}
+	if len(strictThemes) == 0 {
+		return meals, bonusThemes
+	}
+	for _, m := range meals {
+		if matchesThemes(m, strictThemes) {
+			filtered = append(filtered, m)
+		}
+	}
+	if len(filtered) == 0 {
+		return meals, append(bonusThemes, strictThemes...)
+	}
+	return filtered, bonusThemes
+}
+
+// ThemeScore returns the bonus of the meal for the given themes.
+func (s *CalendarTools) ThemeScore(meal *models.MealToFront, themes []models.WeekdayTheme) (res float64) {
+	for _, theme := range themes {
+		if matchesTheme(meal, theme) {
+			res += themeBonus
+		}
+	}
+	return
+}
+
+func matchesThemes(meal *models.MealToFront, themes []models.WeekdayTheme) bool {
+	for _, theme := range themes {
+		if !matchesTheme(meal, theme) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesTheme compares types and tags exactly and ingredients by substring, so
+// "pollo" matches "pechuga de pollo". All comparisons are case insensitive.
+func matchesTheme(meal *models.MealToFront, theme models.WeekdayTheme) bool {
+	var matches bool
+	switch theme.Attribute {
+	case models.ThemeType:
+		matches = strings.EqualFold(meal.Type, theme.Value)
+	case models.ThemeTag:
+		for _, tag := range meal.Tags {
+			if strings.EqualFold(tag, theme.Value) {
+				matches = true
+				break
+			}
+		}
+	case models.ThemeIngredient:
+		value := strings.ToLower(theme.Value)
+		for _, ingredient := range meal.Ingredients {
+			if strings.Contains(strings.ToLower(ingredient), value) {
+				matches = true
+				break
+			}
+		}
+	}
+	return matches != theme.Exclude
+}
@@ -0,0 +1,133 @@
+// Package caldav reads the WebDAV and CalDAV (RFC 4918, RFC 4791) request bodies
+// of PROPFIND and REPORT and writes the multistatus responses. It only knows the
+// XML, the resources and their properties are left to the caller.
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	NamespaceDAV            = "DAV:"
+	NamespaceCalDAV         = "urn:ietf:params:xml:ns:caldav"
+	NamespaceCalendarServer = "http://calendarserver.org/ns/"
+)
+
+// Kinds of request, the name of the root element of the body.
+const (
+	Propfind         = "propfind"
+	CalendarQuery    = "calendar-query"
+	CalendarMultiget = "calendar-multiget"
+)
+
+var ErrInvalidRequest = errors.New("invalid WebDAV request body")
+
+// Request is the body of a PROPFIND or REPORT request. Props are the properties
+// asked for, all of them when AllProp is true. Hrefs are the resources of a
+// calendar-multiget, Start and End the time-range of a calendar-query, zero when
+// not given.
+type Request struct {
+	Kind    string
+	AllProp bool
+	Props   []xml.Name
+	Hrefs   []string
+	Start   time.Time
+	End     time.Time
+}
+
+// ParseRequest reads the body of the request. An empty body is a PROPFIND of
+// all the properties.
+func ParseRequest(r io.Reader) (request Request, err error) {
+	d := xml.NewDecoder(r)
+	var path []string
+	for {
+		token, errToken := d.Token()
+		if errToken == io.EOF {
+			break
+		}
+		if errToken != nil {
+			return Request{}, ErrInvalidRequest
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(path) == 0 {
+				request.Kind = t.Name.Local
+			}
+			parent := ""
+			if len(path) > 0 {
+				parent = path[len(path)-1]
+			}
+			switch {
+			case parent == "prop" && t.Name.Space != "":
+				request.Props = append(request.Props, t.Name)
+			case t.Name.Local == "allprop" && parent == request.Kind:
+				request.AllProp = true
+			case t.Name.Local == "href" && parent == request.Kind:
+				var href string
+				if err = d.DecodeElement(&href, &t); err != nil {
+					return Request{}, ErrInvalidRequest
+				}
+				request.Hrefs = append(request.Hrefs, strings.TrimSpace(href))
+				continue
+			case t.Name.Local == "time-range":
+				if request.Start, request.End, err = timeRange(t.Attr); err != nil {
+					return Request{}, ErrInvalidRequest
+				}
+			}
+			path = append(path, t.Name.Local)
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		case xml.CharData:
+			if len(path) == 0 && len(strings.TrimSpace(string(t))) > 0 {
+				return Request{}, ErrInvalidRequest
+			}
+		}
+	}
+	if request.Kind == "" {
+		return Request{Kind: Propfind, AllProp: true}, nil
+	}
+	if request.Kind != Propfind && request.Kind != CalendarQuery && request.Kind != CalendarMultiget {
+		return Request{}, ErrInvalidRequest
+	}
+	if request.Kind == Propfind && len(request.Props) == 0 {
+		request.AllProp = true
+	}
+	return
+}
+
+// Wants tells whether the property is asked for.
+func (r Request) Wants(name xml.Name) bool {
+	if r.AllProp {
+		return true
+	}
+	for _, p := range r.Props {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// timeRange reads the start and end attributes of a time-range, the rest of
+// them, such as the namespace declarations, are ignored.
+func timeRange(attrs []xml.Attr) (start, end time.Time, err error) {
+	for _, a := range attrs {
+		if a.Name.Space != "" || (a.Name.Local != "start" && a.Name.Local != "end") {
+			continue
+		}
+		var t time.Time
+		if t, err = time.Parse("20060102T150405Z", a.Value); err != nil {
+			return
+		}
+		if a.Name.Local == "start" {
+			start = t
+		} else {
+			end = t
+		}
+	}
+	return
+}
@@ -0,0 +1,115 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRequest(t *testing.T) {
+	request, err := ParseRequest(strings.NewReader(`<?xml version="1.0" encoding="utf-8" ?>
+<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/">
+  <D:prop><D:resourcetype/><CS:getctag/></D:prop>
+</D:propfind>`))
+	assert.NoError(t, err)
+	assert.Equal(t, Propfind, request.Kind)
+	assert.False(t, request.AllProp)
+	assert.Equal(t, []xml.Name{Name("resourcetype"), {Space: NamespaceCalendarServer, Local: "getctag"}}, request.Props)
+	assert.True(t, request.Wants(Name("resourcetype")))
+	assert.False(t, request.Wants(Name("displayname")))
+
+	request, err = ParseRequest(strings.NewReader(`<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">
+    <C:time-range start="20300101T000000Z" end="20300108T000000Z"/>
+  </C:comp-filter></C:comp-filter></C:filter>
+</C:calendar-query>`))
+	assert.NoError(t, err)
+	assert.Equal(t, CalendarQuery, request.Kind)
+	assert.Equal(t, []xml.Name{Name("getetag"), CalName("calendar-data")}, request.Props)
+	assert.Equal(t, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), request.Start)
+	assert.Equal(t, time.Date(2030, time.January, 8, 0, 0, 0, 0, time.UTC), request.End)
+
+	request, err = ParseRequest(strings.NewReader(`<calendar-query xmlns="urn:ietf:params:xml:ns:caldav"><prop xmlns="DAV:">` +
+		`<calendar-data xmlns="urn:ietf:params:xml:ns:caldav"><comp name="VCALENDAR"><allprop></allprop><allcomp></allcomp></comp></calendar-data>` +
+		`<getetag/></prop><filter><comp-filter name="VCALENDAR"><comp-filter name="VEVENT">` +
+		`<time-range xmlns="urn:ietf:params:xml:ns:caldav" start="20300101T000000Z" end="20300108T000000Z"></time-range>` +
+		`</comp-filter></comp-filter></filter></calendar-query>`))
+	assert.NoError(t, err)
+	assert.False(t, request.AllProp, "the allprop of calendar-data is not the one of the request")
+	assert.Equal(t, []xml.Name{CalName("calendar-data"), Name("getetag")}, request.Props)
+	assert.Equal(t, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), request.Start)
+
+	request, err = ParseRequest(strings.NewReader(`<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/></D:prop>
+  <D:href>/caldav/1/20300101.ics</D:href>
+  <D:href> /caldav/1/20300102.ics </D:href>
+</C:calendar-multiget>`))
+	assert.NoError(t, err)
+	assert.Equal(t, CalendarMultiget, request.Kind)
+	assert.Equal(t, []string{"/caldav/1/20300101.ics", "/caldav/1/20300102.ics"}, request.Hrefs)
+
+	request, err = ParseRequest(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Equal(t, Request{Kind: Propfind, AllProp: true}, request)
+}
+
+func TestParseRequest_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not xml", data: "propfind"},
+		{name: "not closed", data: `<D:propfind xmlns:D="DAV:"><D:prop>`},
+		{name: "unknown report", data: `<D:sync-collection xmlns:D="DAV:"/>`},
+		{name: "wrong time range", data: `<C:calendar-query xmlns:C="urn:ietf:params:xml:ns:caldav"><C:time-range start="2030-01-01"/></C:calendar-query>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRequest(strings.NewReader(tt.data))
+			assert.ErrorIs(t, err, ErrInvalidRequest)
+		})
+	}
+}
+
+func TestWriteMultistatus(t *testing.T) {
+	var b strings.Builder
+	err := WriteMultistatus(&b, []Response{
+		{
+			Href: "/caldav/1/",
+			Props: []Prop{
+				{Name: Name("resourcetype"), Children: []xml.StartElement{{Name: Name("collection")}, {Name: CalName("calendar")}}},
+				{Name: Name("displayname"), Text: "Comidas & más"},
+			},
+			Missing: []xml.Name{Name("owner")},
+		},
+		{Href: "/caldav/1/20300101.ics", Status: 404},
+	})
+	assert.NoError(t, err)
+
+	var multistatus struct {
+		Responses []struct {
+			Href     string `xml:"DAV: href"`
+			Status   string `xml:"DAV: status"`
+			Propstat []struct {
+				Prop struct {
+					Inner string `xml:",innerxml"`
+				} `xml:"DAV: prop"`
+				Status string `xml:"DAV: status"`
+			} `xml:"DAV: propstat"`
+		} `xml:"DAV: response"`
+	}
+	assert.NoError(t, xml.Unmarshal([]byte(b.String()), &multistatus))
+	assert.Len(t, multistatus.Responses, 2)
+	first := multistatus.Responses[0]
+	assert.Equal(t, "/caldav/1/", first.Href)
+	assert.Len(t, first.Propstat, 2)
+	assert.Equal(t, "HTTP/1.1 200 OK", first.Propstat[0].Status)
+	assert.Contains(t, first.Propstat[0].Prop.Inner, "Comidas &amp; más")
+	assert.Contains(t, first.Propstat[0].Prop.Inner, `<calendar xmlns="urn:ietf:params:xml:ns:caldav">`)
+	assert.Equal(t, "HTTP/1.1 404 Not Found", first.Propstat[1].Status)
+	assert.Equal(t, "HTTP/1.1 404 Not Found", multistatus.Responses[1].Status)
+}
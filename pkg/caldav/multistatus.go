@@ -0,0 +1,99 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Prop is a property of a resource. Its value is either the text, an href or the
+// empty child elements, as the resourcetype or the supported-calendar-component-set.
+type Prop struct {
+	Name     xml.Name
+	Text     string
+	Href     string
+	Children []xml.StartElement
+}
+
+// Response is the response of a resource in a multistatus. Resources with a
+// status, such as a missing one, have no properties. Missing are the properties
+// asked for that the resource does not have.
+type Response struct {
+	Href    string
+	Status  int
+	Props   []Prop
+	Missing []xml.Name
+}
+
+// Name returns the name of a property in the DAV: namespace.
+func Name(local string) xml.Name {
+	return xml.Name{Space: NamespaceDAV, Local: local}
+}
+
+// CalName returns the name of a property in the CalDAV namespace.
+func CalName(local string) xml.Name {
+	return xml.Name{Space: NamespaceCalDAV, Local: local}
+}
+
+// WriteMultistatus writes the 207 Multi-Status body of the responses.
+func WriteMultistatus(w io.Writer, responses []Response) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	multistatus := xml.StartElement{Name: Name("multistatus")}
+	tokens := []xml.Token{multistatus}
+	for _, r := range responses {
+		tokens = append(tokens, xml.StartElement{Name: Name("response")})
+		tokens = append(tokens, textElement(Name("href"), r.Href)...)
+		if r.Status != 0 {
+			tokens = append(tokens, textElement(Name("status"), status(r.Status))...)
+		} else {
+			tokens = append(tokens, propstat(r.Props, nil, http.StatusOK)...)
+			if len(r.Missing) > 0 {
+				tokens = append(tokens, propstat(nil, r.Missing, http.StatusNotFound)...)
+			}
+		}
+		tokens = append(tokens, xml.EndElement{Name: Name("response")})
+	}
+	tokens = append(tokens, multistatus.End())
+	for _, t := range tokens {
+		if err := e.EncodeToken(t); err != nil {
+			return err
+		}
+	}
+	return e.Flush()
+}
+
+func propstat(props []Prop, missing []xml.Name, code int) (tokens []xml.Token) {
+	tokens = append(tokens, xml.StartElement{Name: Name("propstat")}, xml.StartElement{Name: Name("prop")})
+	for _, p := range props {
+		start := xml.StartElement{Name: p.Name}
+		tokens = append(tokens, start)
+		if p.Text != "" {
+			tokens = append(tokens, xml.CharData(p.Text))
+		}
+		if p.Href != "" {
+			tokens = append(tokens, textElement(Name("href"), p.Href)...)
+		}
+		for _, child := range p.Children {
+			tokens = append(tokens, child, child.End())
+		}
+		tokens = append(tokens, start.End())
+	}
+	for _, name := range missing {
+		tokens = append(tokens, xml.StartElement{Name: name}, xml.EndElement{Name: name})
+	}
+	tokens = append(tokens, xml.EndElement{Name: Name("prop")})
+	tokens = append(tokens, textElement(Name("status"), status(code))...)
+	return append(tokens, xml.EndElement{Name: Name("propstat")})
+}
+
+func textElement(name xml.Name, text string) []xml.Token {
+	return []xml.Token{xml.StartElement{Name: name}, xml.CharData(text), xml.EndElement{Name: name}}
+}
+
+func status(code int) string {
+	return "HTTP/1.1 " + strconv.Itoa(code) + " " + http.StatusText(code)
+}
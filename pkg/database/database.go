@@ -33,7 +33,11 @@ func SqlLiteConnect(bbddName string) (*sqlx.DB, error) {
 	db, err := sqlx.Connect("sqlite", filepath.Dir(dir)+bbddName)
 
 	numbSc, err := GetDBVersion(db)
-	if numbSc < len(scripts)-1 {
+	if err == nil {
+		// the stored version is the last script executed
+		numbSc++
+	}
+	if numbSc < len(scripts) {
 		err = CreateScripts(db, numbSc)
 		if err != nil {
 			return db, err
@@ -27,6 +27,70 @@ var scripts = []DbScript{
 		Script:      addNameToCalendars,
 		Description: "add name column to calendar",
 	},
+	{
+		Script:      templates,
+		Description: "templates tables",
+	},
+	{
+		Script:      recurringRules,
+		Description: "recurring rules table",
+	},
+	{
+		Script:      weekdayThemes,
+		Description: "weekday themes table",
+	},
+	{
+		Script:      userSettings,
+		Description: "user settings table and kcal column in calendar",
+	},
+	{
+		Script:      ingredientSettings,
+		Description: "add ingredient variety columns to user settings",
+	},
+	{
+		Script:      ingredientExclusions,
+		Description: "ingredient exclusions table",
+	},
+	{
+		Script:      hemisphereSettings,
+		Description: "add hemisphere to user settings",
+	},
+	{
+		Script:      specialDates,
+		Description: "special dates table and holidays settings",
+	},
+	{
+		Script:      typePolicies,
+		Description: "meal type policies table",
+	},
+	{
+		Script:      prepTimeSettings,
+		Description: "add preparation time budget to user settings",
+	},
+	{
+		Script:      leftovers,
+		Description: "meal yields table and leftovers link in calendar",
+	},
+	{
+		Script:      servings,
+		Description: "add servings to calendar days and user settings",
+	},
+	{
+		Script:      pantry,
+		Description: "pantry table",
+	},
+	{
+		Script:      ingredientReuse,
+		Description: "add ingredient reuse weight to user settings",
+	},
+	{
+		Script:      costs,
+		Description: "meal costs and ingredient prices tables and weekly budget",
+	},
+	{
+		Script:      feedTokens,
+		Description: "calendar feed tokens and versions tables",
+	},
 }
 var version = `
 CREATE TABLE IF NOT EXISTS db_version (
@@ -69,3 +133,185 @@ CREATE TABLE IF NOT EXISTS calendar (
 var addNameToCalendars = `
 ALTER TABLE calendar ADD name text NOT NULL;
 `
+
+var templates = `
+CREATE TABLE IF NOT EXISTS templates (
+	id		 text   NOT NULL,
+	user_id	 text   NOT NULL,
+	name	 text   NOT NULL,
+	PRIMARY KEY (id,user_id)
+);
+
+CREATE TABLE IF NOT EXISTS template_days (
+	template_id	text    NOT NULL,
+	user_id		text    NOT NULL,
+	weekday		integer NOT NULL,
+	meal_id		text    NOT NULL DEFAULT '',
+	meal_type	text    NOT NULL DEFAULT '',
+	PRIMARY KEY (template_id,user_id,weekday)
+);
+`
+
+var recurringRules = `
+CREATE TABLE IF NOT EXISTS recurring_rules (
+	id		 text   NOT NULL,
+	user_id	 text   NOT NULL,
+	meal_id	 text   NOT NULL,
+	rule	 text   NOT NULL,
+	start	 text   NOT NULL,
+	PRIMARY KEY (id,user_id)
+);
+`
+
+var weekdayThemes = `
+CREATE TABLE IF NOT EXISTS weekday_themes (
+	id		  text    NOT NULL,
+	user_id	  text    NOT NULL,
+	weekday	  integer NOT NULL,
+	name	  text    NOT NULL DEFAULT '',
+	attribute text    NOT NULL,
+	value	  text    NOT NULL,
+	exclude	  boolean NOT NULL DEFAULT false,
+	strict	  boolean NOT NULL DEFAULT false,
+	PRIMARY KEY (id,user_id)
+);
+`
+
+var userSettings = `
+ALTER TABLE calendar ADD kcal integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS user_settings (
+	user_id		    text    PRIMARY KEY,
+	daily_kcal_min	integer NOT NULL DEFAULT 0,
+	daily_kcal_max	integer NOT NULL DEFAULT 0,
+	weekly_kcal_min	integer NOT NULL DEFAULT 0,
+	weekly_kcal_max	integer NOT NULL DEFAULT 0
+);
+`
+
+var ingredientSettings = `
+ALTER TABLE user_settings ADD ingredient_spacing integer NOT NULL DEFAULT 2;
+ALTER TABLE user_settings ADD main_ingredients text NOT NULL DEFAULT '[]';
+`
+
+var ingredientExclusions = `
+CREATE TABLE IF NOT EXISTS ingredient_exclusions (
+	id			text   NOT NULL,
+	user_id		text   NOT NULL,
+	ingredient	text   NOT NULL,
+	from_date	text   NOT NULL DEFAULT '',
+	until_date	text   NOT NULL DEFAULT '',
+	reason		text   NOT NULL DEFAULT '',
+	PRIMARY KEY (id,user_id)
+);
+`
+
+var hemisphereSettings = `
+ALTER TABLE user_settings ADD hemisphere text NOT NULL DEFAULT 'norte';
+`
+
+var specialDates = `
+ALTER TABLE user_settings ADD holiday_country text NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD holiday_region text NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS special_dates (
+	id			text   NOT NULL,
+	user_id		text   NOT NULL,
+	date		text   NOT NULL,
+	name		text   NOT NULL DEFAULT '',
+	source		text   NOT NULL DEFAULT 'custom',
+	PRIMARY KEY (id,user_id)
+);
+`
+
+var typePolicies = `
+CREATE TABLE IF NOT EXISTS type_policies (
+	user_id		text    NOT NULL,
+	type		text    NOT NULL,
+	frequency	integer NOT NULL DEFAULT 0,
+	min_spacing	integer NOT NULL DEFAULT 0,
+	weekdays	text    NOT NULL DEFAULT '[]',
+	holidays	boolean NOT NULL DEFAULT false,
+	max_count	integer NOT NULL DEFAULT 0,
+	window_days	integer NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id,type)
+);
+`
+
+var prepTimeSettings = `
+ALTER TABLE user_settings ADD max_prep_time text NOT NULL DEFAULT '[]';
+ALTER TABLE user_settings ADD default_prep_time integer NOT NULL DEFAULT 30;
+`
+
+var leftovers = `
+ALTER TABLE calendar ADD leftover_of text NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS meal_yields (
+	user_id		text    NOT NULL,
+	meal_id		text    NOT NULL,
+	days		integer NOT NULL,
+	offset_days	integer NOT NULL DEFAULT 1,
+	PRIMARY KEY (user_id,meal_id)
+);
+`
+
+var servings = `
+ALTER TABLE calendar ADD servings integer NOT NULL DEFAULT 2;
+ALTER TABLE user_settings ADD default_servings integer NOT NULL DEFAULT 2;
+ALTER TABLE user_settings ADD high_servings integer NOT NULL DEFAULT 0;
+ALTER TABLE user_settings ADD servings_bonus real NOT NULL DEFAULT 1.5;
+`
+
+var pantry = `
+CREATE TABLE IF NOT EXISTS pantry (
+	id			text   NOT NULL,
+	user_id		text   NOT NULL,
+	ingredient	text   NOT NULL,
+	quantity	real   NOT NULL DEFAULT 0,
+	unit		text   NOT NULL DEFAULT '',
+	expiry		text   NOT NULL DEFAULT '',
+	PRIMARY KEY (id,user_id)
+);
+`
+
+var ingredientReuse = `
+ALTER TABLE user_settings ADD ingredient_reuse real NOT NULL DEFAULT 0;
+`
+
+var costs = `
+CREATE TABLE IF NOT EXISTS meal_costs (
+	user_id		text    NOT NULL,
+	meal_id		text    NOT NULL,
+	cost		real    NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id,meal_id)
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_prices (
+	id			text   NOT NULL,
+	user_id		text   NOT NULL,
+	ingredient	text   NOT NULL,
+	price		real   NOT NULL DEFAULT 0,
+	quantity	real   NOT NULL DEFAULT 0,
+	unit		text   NOT NULL DEFAULT '',
+	PRIMARY KEY (id,user_id)
+);
+
+ALTER TABLE user_settings ADD weekly_budget real NOT NULL DEFAULT 0;
+`
+
+var feedTokens = `
+CREATE TABLE IF NOT EXISTS feed_tokens (
+	user_id		text    NOT NULL,
+	token_hash	text    NOT NULL UNIQUE,
+	created_at	text    NOT NULL,
+	PRIMARY KEY (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS feed_versions (
+	user_id		text    NOT NULL,
+	options		text    NOT NULL,
+	etag		text    NOT NULL,
+	modified_at	text    NOT NULL,
+	PRIMARY KEY (user_id, options)
+);
+`
@@ -0,0 +1,189 @@
+// Package holidays knows the special days of a calendar: public holidays of a
+// country and region, loaded from the bundled data files or from an iCalendar
+// file, and any other date such as a birthday.
+//
+// A holiday date is one of "MM/dd" (every year), "yyyy/MM/dd" (once) or
+// "easter+N" / "easter-N" (N days from Easter Sunday, every year).
+package holidays
+
+import (
+	"calendar/pkg/ical"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed data/*.json
+var data embed.FS
+
+var (
+	ErrUnknownCountry = errors.New("unknown holidays country")
+	ErrUnknownRegion  = errors.New("unknown holidays region")
+	ErrInvalidDate    = errors.New("invalid holiday date")
+)
+
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+type List []Holiday
+
+type country struct {
+	Country  string          `json:"country"`
+	Name     string          `json:"name"`
+	Holidays List            `json:"holidays"`
+	Regions  map[string]List `json:"regions"`
+}
+
+// Countries returns the codes of the bundled countries.
+func Countries() (codes []string) {
+	files, _ := data.ReadDir("data")
+	for _, f := range files {
+		codes = append(codes, strings.ToUpper(strings.TrimSuffix(f.Name(), ".json")))
+	}
+	sort.Strings(codes)
+	return
+}
+
+// Load returns the bundled holidays of the country plus the ones of the region,
+// if any is given.
+func Load(countryCode, region string) (holidays List, err error) {
+	file, err := data.ReadFile("data/" + strings.ToLower(countryCode) + ".json")
+	if err != nil {
+		return nil, ErrUnknownCountry
+	}
+	var c country
+	if err = json.Unmarshal(file, &c); err != nil {
+		return nil, err
+	}
+	holidays = append(holidays, c.Holidays...)
+	if region == "" {
+		return
+	}
+	regional, ok := c.Regions[strings.ToUpper(region)]
+	if !ok {
+		return nil, ErrUnknownRegion
+	}
+	return append(holidays, regional...), nil
+}
+
+// ParseICS returns a holiday for every event of the iCalendar file. Events with
+// a yearly recurrence rule repeat every year, the rest happen once.
+func ParseICS(r io.Reader) (holidays List, err error) {
+	calendar, err := ical.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range calendar.Events() {
+		start, ok := event.Get("DTSTART")
+		if !ok {
+			return nil, ical.ErrInvalidCalendar
+		}
+		date, errDate := start.Date()
+		if errDate != nil {
+			return nil, errDate
+		}
+		holiday := Holiday{Date: date.Format("2006/01/02")}
+		if summary, ok := event.Get("SUMMARY"); ok {
+			holiday.Name = summary.Text()
+		}
+		if rule, ok := event.Get("RRULE"); ok && strings.Contains(strings.ToUpper(rule.Value), "FREQ=YEARLY") {
+			holiday.Date = date.Format("01/02")
+		}
+		holidays = append(holidays, holiday)
+	}
+	return
+}
+
+// Validate checks the format of a holiday date.
+func Validate(date string) error {
+	_, err := parseDate(date)
+	return err
+}
+
+// Find returns the first holiday of the list on the date.
+func (l List) Find(date time.Time) (holiday Holiday, ok bool) {
+	for _, h := range l {
+		if h.Matches(date) {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// Matches reports whether the holiday falls on the date.
+func (h Holiday) Matches(date time.Time) bool {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	d, err := parseDate(h.Date)
+	if err != nil {
+		return false
+	}
+	switch d.kind {
+	case once:
+		return d.value.Equal(date)
+	case yearly:
+		return d.value.Month() == date.Month() && d.value.Day() == date.Day()
+	default:
+		return Easter(date.Year()).AddDate(0, 0, d.offset).Equal(date)
+	}
+}
+
+// Easter returns the Easter Sunday of the gregorian year.
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+type dateKind int
+
+const (
+	once dateKind = iota
+	yearly
+	easter
+)
+
+type holidayDate struct {
+	kind   dateKind
+	value  time.Time
+	offset int
+}
+
+func parseDate(date string) (d holidayDate, err error) {
+	if strings.HasPrefix(date, "easter") {
+		d.kind = easter
+		if rest := strings.TrimPrefix(date, "easter"); rest != "" {
+			if d.offset, err = strconv.Atoi(rest); err != nil {
+				return holidayDate{}, ErrInvalidDate
+			}
+		}
+		return d, nil
+	}
+	if d.value, err = time.Parse("2006/01/02", date); err == nil {
+		d.kind = once
+		return d, nil
+	}
+	if d.value, err = time.Parse("01/02", date); err == nil {
+		d.kind = yearly
+		return d, nil
+	}
+	return holidayDate{}, ErrInvalidDate
+}
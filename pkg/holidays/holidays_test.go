@@ -0,0 +1,65 @@
+package holidays
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse("2006/01/02", s)
+	return t
+}
+
+func TestEaster(t *testing.T) {
+	assert.Equal(t, date("2023/04/09"), Easter(2023))
+	assert.Equal(t, date("2024/03/31"), Easter(2024))
+	assert.Equal(t, date("2025/04/20"), Easter(2025))
+}
+
+func TestLoad(t *testing.T) {
+	national, err := Load("es", "")
+	assert.NoError(t, err)
+	regional, err := Load("ES", "md")
+	assert.NoError(t, err)
+	assert.Greater(t, len(regional), len(national))
+
+	_, ok := national.Find(date("2024/03/29"))
+	assert.True(t, ok, "good friday")
+	_, ok = national.Find(date("2024/05/02"))
+	assert.False(t, ok)
+	holiday, ok := regional.Find(date("2024/05/02"))
+	assert.True(t, ok)
+	assert.Equal(t, "Fiesta de la Comunidad de Madrid", holiday.Name)
+
+	_, err = Load("xx", "")
+	assert.ErrorIs(t, err, ErrUnknownCountry)
+	_, err = Load("es", "xx")
+	assert.ErrorIs(t, err, ErrUnknownRegion)
+	assert.Contains(t, Countries(), "ES")
+}
+
+func TestParseICS(t *testing.T) {
+	data := "BEGIN:VCALENDAR\nVERSION:2.0\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:19900314\nRRULE:FREQ=YEARLY\nSUMMARY:Cumpleaños\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20240920\nSUMMARY:Fiesta local\nEND:VEVENT\n" +
+		"END:VCALENDAR\n"
+	holidays, err := ParseICS(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, List{{Date: "03/14", Name: "Cumpleaños"}, {Date: "2024/09/20", Name: "Fiesta local"}}, holidays)
+	_, ok := holidays.Find(date("2030/03/14"))
+	assert.True(t, ok)
+	_, ok = holidays.Find(date("2025/09/20"))
+	assert.False(t, ok)
+}
+
+func TestValidate(t *testing.T) {
+	for _, d := range []string{"12/25", "2024/12/25", "easter", "easter-2", "easter+1"} {
+		assert.NoError(t, Validate(d), d)
+	}
+	for _, d := range []string{"", "25/12", "easter+", "eastern", "2024-12-25"} {
+		assert.ErrorIs(t, Validate(d), ErrInvalidDate, d)
+	}
+}
@@ -0,0 +1,195 @@
+// Package ical reads and writes the RFC 5545 iCalendar format. Lines are
+// unfolded and split into properties, grouped by the BEGIN and END of their
+// components. Property values are kept as written, Text unescapes TEXT values
+// and NewText escapes them. Encode folds the lines back.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCalendar = errors.New("invalid iCalendar data")
+
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Parse reads the first VCALENDAR of the reader.
+func Parse(r io.Reader) (calendar *Component, err error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var stack []*Component
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, errLine := parseLine(line)
+		if errLine != nil {
+			return nil, errLine
+		}
+		switch p.Name {
+		case "BEGIN":
+			c := &Component{Name: strings.ToUpper(p.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, c)
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(p.Value) {
+				return nil, ErrInvalidCalendar
+			}
+			if len(stack) == 1 {
+				if stack[0].Name != "VCALENDAR" {
+					return nil, ErrInvalidCalendar
+				}
+				return stack[0], nil
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, ErrInvalidCalendar
+			}
+			c := stack[len(stack)-1]
+			c.Properties = append(c.Properties, p)
+		}
+	}
+	return nil, ErrInvalidCalendar
+}
+
+// Events returns the VEVENT components of the calendar.
+func (c *Component) Events() (events []*Component) {
+	for _, child := range c.Components {
+		if child.Name == "VEVENT" {
+			events = append(events, child)
+		}
+	}
+	return
+}
+
+// Get returns the first property with the given name.
+func (c *Component) Get(name string) (p Property, ok bool) {
+	for _, p = range c.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Property{}, false
+}
+
+// Text returns the unescaped TEXT value of the property.
+func (p Property) Text() string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range p.Value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Date returns the day of a DATE or DATE-TIME value. Times are not converted
+// between time zones, the day is the one written in the value.
+func (p Property) Date() (date time.Time, err error) {
+	value := p.Value
+	if i := strings.IndexByte(value, 'T'); i >= 0 {
+		value = value[:i]
+	}
+	if date, err = time.Parse("20060102", value); err != nil {
+		return time.Time{}, ErrInvalidCalendar
+	}
+	return
+}
+
+// unfold joins the lines starting with a space or a tab to the previous one.
+func unfold(r io.Reader) (lines []string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits "NAME;PARAM=value:VALUE". Colons and semicolons inside
+// quoted parameter values are not separators.
+func parseLine(line string) (p Property, err error) {
+	quoted := false
+	end := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return Property{}, ErrInvalidCalendar
+	}
+	p.Value = line[end+1:]
+	parts := splitUnquoted(line[:end], ';')
+	p.Name = strings.ToUpper(parts[0])
+	if p.Name == "" {
+		return Property{}, ErrInvalidCalendar
+	}
+	for _, param := range parts[1:] {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return Property{}, ErrInvalidCalendar
+		}
+		if p.Params == nil {
+			p.Params = map[string]string{}
+		}
+		p.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return
+}
+
+func splitUnquoted(s string, sep rune) (parts []string) {
+	quoted := false
+	start := 0
+	for i, r := range s {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == sep && !quoted {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,100 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const calendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//test//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:1@test\r\n" +
+	"DTSTART;VALUE=DATE:20230624\r\n" +
+	"SUMMARY:Sant Joan\\, revetlla\r\n" +
+	"DESCRIPTION:first line\\nsecond\r\n" +
+	"  line\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:2@test\r\n" +
+	"DTSTART;TZID=\"Europe/Madrid:Spain\":20231225T130000\r\n" +
+	"SUMMARY:Navidad\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	c, err := Parse(strings.NewReader(calendar))
+	assert.NoError(t, err)
+	events := c.Events()
+	assert.Len(t, events, 2)
+
+	summary, ok := events[0].Get("SUMMARY")
+	assert.True(t, ok)
+	assert.Equal(t, "Sant Joan, revetlla", summary.Text())
+	description, _ := events[0].Get("DESCRIPTION")
+	assert.Equal(t, "first line\nsecond line", description.Text())
+	start, _ := events[0].Get("DTSTART")
+	assert.Equal(t, "DATE", start.Params["VALUE"])
+	date, err := start.Date()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, time.June, 24, 0, 0, 0, 0, time.UTC), date)
+
+	start, _ = events[1].Get("DTSTART")
+	assert.Equal(t, "Europe/Madrid:Spain", start.Params["TZID"])
+	date, err = start.Date()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, time.December, 25, 0, 0, 0, 0, time.UTC), date)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "not a calendar", data: "BEGIN:VEVENT\nEND:VEVENT\n"},
+		{name: "not closed", data: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VEVENT\n"},
+		{name: "wrong end", data: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n"},
+		{name: "line without value", data: "BEGIN:VCALENDAR\nVERSION\nEND:VCALENDAR\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.data))
+			assert.ErrorIs(t, err, ErrInvalidCalendar)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	event := &Component{Name: "VEVENT", Properties: []Property{
+		{Name: "UID", Value: "20230605-1@test"},
+		{Name: "DTSTART", Params: map[string]string{"VALUE": "DATE"}, Value: "20230605"},
+		NewText("SUMMARY", "Lentejas, chorizo; pan\\vino"),
+		NewText("DESCRIPTION", "Ingredientes:\n- "+strings.Repeat("calabacín ", 12)),
+		{Name: "X-TEST", Params: map[string]string{"X-NOTE": "a:b"}, Value: "1"},
+	}}
+	var b strings.Builder
+	err := Encode(&b, &Component{Name: "VCALENDAR", Properties: []Property{{Name: "VERSION", Value: "2.0"}}, Components: []*Component{event}})
+	assert.NoError(t, err)
+	out := b.String()
+
+	assert.True(t, strings.HasSuffix(out, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, out, `SUMMARY:Lentejas\, chorizo\; pan\\vino`+"\r\n")
+	assert.Contains(t, out, "X-TEST;X-NOTE=\"a:b\":1\r\n")
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, utf8.ValidString(line), line)
+	}
+
+	c, err := Parse(strings.NewReader(out))
+	assert.NoError(t, err)
+	parsed := c.Events()[0]
+	summary, _ := parsed.Get("SUMMARY")
+	assert.Equal(t, "Lentejas, chorizo; pan\\vino", summary.Text())
+	description, _ := parsed.Get("DESCRIPTION")
+	assert.Equal(t, "Ingredientes:\n- "+strings.Repeat("calabacín ", 12), description.Text())
+}
@@ -0,0 +1,80 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the length of the lines written, CRLF excluded. Longer lines
+// are folded.
+const maxLineOctets = 75
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// NewText returns a property with the TEXT value escaped.
+func NewText(name, text string) Property {
+	return Property{Name: name, Value: textEscaper.Replace(text)}
+}
+
+// Encode writes the component and its subcomponents with CRLF line endings,
+// folding the lines longer than 75 octets.
+func Encode(w io.Writer, c *Component) error {
+	bw := bufio.NewWriter(w)
+	encode(bw, c)
+	return bw.Flush()
+}
+
+func encode(w *bufio.Writer, c *Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		writeLine(w, p.String())
+	}
+	for _, child := range c.Components {
+		encode(w, child)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// String returns the content line of the property, unfolded. Parameter values
+// with colons, semicolons or commas are quoted.
+func (p Property) String() string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	for _, name := range sortedKeys(p.Params) {
+		value := p.Params[name]
+		if strings.ContainsAny(value, ":;,") {
+			value = `"` + value + `"`
+		}
+		b.WriteString(";" + name + "=" + value)
+	}
+	b.WriteString(":" + p.Value)
+	return b.String()
+}
+
+// writeLine writes the line folded in lines of up to maxLineOctets octets, the
+// continuation ones starting with a space. Multi-octet characters are not split.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line + "\r\n")
+}
+
+func sortedKeys(params map[string]string) []string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
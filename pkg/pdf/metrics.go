@@ -0,0 +1,55 @@
+package pdf
+
+// Widths of the printable ASCII characters, from the space to the tilde, in
+// thousandths of the font size, from the Adobe metrics of the standard fonts.
+var (
+	helvetica = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+		278, 278, 584, 584, 584, 556, 1015, // : to @
+		667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+		722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+		278, 278, 278, 469, 556, 333, // [ to `
+		556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+		556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+		334, 260, 334, 584, // { to ~
+	}
+	helveticaBold = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+		333, 333, 584, 584, 584, 611, 975,
+		722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833,
+		722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+		333, 278, 333, 584, 556, 333,
+		556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889,
+		611, 611, 611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500,
+		389, 280, 389, 584,
+	}
+)
+
+// accented are the letters as wide as the letter without the accent.
+var accented = map[rune]rune{
+	'á': 'a', 'à': 'a', 'ä': 'a', 'â': 'a', 'é': 'e', 'è': 'e', 'ë': 'e', 'ê': 'e',
+	'ó': 'o', 'ò': 'o', 'ö': 'o', 'ô': 'o', 'ú': 'u', 'ù': 'u', 'ü': 'u', 'û': 'u',
+	'ñ': 'n', 'ç': 'c', 'Á': 'A', 'À': 'A', 'É': 'E', 'È': 'E', 'Í': 'I', 'Ì': 'I',
+	'Ó': 'O', 'Ò': 'O', 'Ú': 'U', 'Ù': 'U', 'Ü': 'U', 'Ñ': 'N', 'Ç': 'C',
+}
+
+// symbols are the characters as wide in both fonts. The accented i is drawn on
+// the dotless i, wider than the i.
+var symbols = map[rune]int{
+	'í': 278, 'ì': 278, 'ï': 278, 'î': 278, '¿': 611, '¡': 333, 'º': 365, 'ª': 370, '€': 556,
+}
+
+func runeWidth(r rune, widths [95]int) int {
+	if width, ok := symbols[r]; ok {
+		return width
+	}
+	if base, ok := accented[r]; ok {
+		r = base
+	}
+	if r >= ' ' && r <= '~' {
+		return widths[r-' ']
+	}
+	return 556
+}
@@ -0,0 +1,156 @@
+// Package pdf writes simple PDF documents made of text, lines and rectangles.
+// Text is set in the standard Helvetica fonts, which every reader has, so no
+// font is embedded, and encoded as WinAnsi, which covers the Spanish letters.
+// Coordinates are in points from the top left corner of the page.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Page sizes in points.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+type Document struct {
+	width  float64
+	height float64
+	pages  []*Page
+}
+
+type Page struct {
+	height  float64
+	content bytes.Buffer
+}
+
+// New returns an empty document whose pages have the given size.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// AddPage adds a blank page at the end of the document and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{height: d.height}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text writes the text with its baseline at y.
+func (p *Page) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, number(size), number(x), number(p.height-y), escape(encode(text)))
+}
+
+// Line strokes a line of the given width.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", number(width), number(x1), number(p.height-y1), number(x2), number(p.height-y2))
+}
+
+// Rect fills the rectangle with the gray level, from 0 black to 1 white.
+func (p *Page) Rect(x, y, width, height, gray float64) {
+	fmt.Fprintf(&p.content, "%s g %s %s %s %s re f 0 g\n", number(gray), number(x), number(p.height-y-height), number(width), number(height))
+}
+
+// Encode writes the document. Its pages share the two fonts.
+func (d *Document) Encode(w io.Writer) error {
+	var b bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = strconv.Itoa(5+2*i) + " 0 R"
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(d.pages), number(d.width), number(d.height)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// TextWidth returns the width of the text in points.
+func TextWidth(text string, size float64, bold bool) float64 {
+	widths := helvetica
+	if bold {
+		widths = helveticaBold
+	}
+	var width int
+	for _, r := range text {
+		width += runeWidth(r, widths)
+	}
+	return float64(width) * size / 1000
+}
+
+// Wrap splits the text into lines no wider than width, breaking at spaces.
+// Words wider than width are left on a line of their own.
+func Wrap(text string, size float64, bold bool, width float64) (lines []string) {
+	var line string
+	for _, word := range strings.Fields(text) {
+		if line != "" && TextWidth(line+" "+word, size, bold) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return
+}
+
+func number(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+// encode returns the text in WinAnsi. Latin-1 letters keep their code and the
+// runes it does not have are replaced with a question mark.
+func encode(text string) string {
+	b := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			b = append(b, byte(r))
+		case winAnsi[r] != 0:
+			b = append(b, winAnsi[r])
+		default:
+			b = append(b, '?')
+		}
+	}
+	return string(b)
+}
+
+// winAnsi are the codes of the runes out of Latin-1 in WinAnsi.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
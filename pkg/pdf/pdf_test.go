@@ -0,0 +1,54 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	d := New(A4Height, A4Width)
+	p := d.AddPage()
+	p.Text(40, 50, 12, true, "Menú (semana 1)")
+	p.Line(40, 60, 200, 60, 0.5)
+	p.Rect(40, 70, 100, 20, 0.9)
+	d.AddPage().Text(40, 50, 10, false, `Lentejas \ arroz €`)
+	var b bytes.Buffer
+	assert.NoError(t, d.Encode(&b))
+	out := b.Bytes()
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 2 /MediaBox [0 0 841.89 595.28]")
+	assert.Contains(t, string(out), "BT /F2 12 Tf 40 545.28 Td (Men\xfa \\(semana 1\\)) Tj ET\n")
+	assert.Contains(t, string(out), "0.5 w 40 535.28 m 200 535.28 l S\n")
+	assert.Contains(t, string(out), "0.9 g 40 505.28 100 20 re f 0 g\n")
+	assert.Contains(t, string(out), "(Lentejas \\\\ arroz \x80) Tj")
+
+	// Every object is where the cross-reference table says.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	assert.NotNil(t, startxref)
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	assert.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n0 9\n")))
+	for i, offset := range regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1) {
+		o, _ := strconv.Atoi(string(offset[1]))
+		assert.True(t, bytes.HasPrefix(out[o:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 6.67, TextWidth("A", 10, false))
+	assert.Equal(t, 5.0, TextWidth("z", 10, false))
+	assert.Equal(t, 5.84, TextWidth("~", 10, true))
+	assert.Equal(t, TextWidth("a", 10, false), TextWidth("á", 10, false))
+	assert.Equal(t, 2.78, TextWidth("í", 10, true))
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"Lentejas con", "chorizo"}, Wrap("Lentejas con chorizo", 10, false, 70))
+	assert.Equal(t, []string{"Supercalifragilístico", "y"}, Wrap("Supercalifragilístico y", 10, false, 20))
+	assert.Empty(t, Wrap("  ", 10, false, 20))
+}
@@ -0,0 +1,268 @@
+// Package rrule implements the subset of the RFC 5545 recurrence rules needed to
+// know whether a day is an occurrence of a rule. Only day granularity is
+// supported: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL,
+// BYDAY (with ordinals for MONTHLY and YEARLY rules), BYMONTHDAY and BYMONTH.
+package rrule
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY value. N is the ordinal of the weekday inside the month
+// or year (negative counts from the end), 0 means every such weekday.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	Start      time.Time
+}
+
+// Parse parses a rule such as "FREQ=MONTHLY;BYDAY=1SU" whose first occurrence
+// can not be before start. The "RRULE:" prefix is optional.
+func Parse(rule string, start time.Time) (r *Rule, err error) {
+	r = &Rule{Interval: 1, Start: day(start)}
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return nil, ErrInvalidRule
+		}
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			if r.Freq != Daily && r.Freq != Weekly && r.Freq != Monthly && r.Freq != Yearly {
+				return nil, ErrInvalidRule
+			}
+		case "INTERVAL":
+			if r.Interval, err = strconv.Atoi(value); err != nil || r.Interval < 1 {
+				return nil, ErrInvalidRule
+			}
+		case "COUNT":
+			if r.Count, err = strconv.Atoi(value); err != nil || r.Count < 1 {
+				return nil, ErrInvalidRule
+			}
+		case "UNTIL":
+			if len(value) < 8 {
+				return nil, ErrInvalidRule
+			}
+			if r.Until, err = time.Parse("20060102", value[:8]); err != nil {
+				return nil, ErrInvalidRule
+			}
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				if len(v) < 2 {
+					return nil, ErrInvalidRule
+				}
+				wd, ok := weekdays[v[len(v)-2:]]
+				if !ok {
+					return nil, ErrInvalidRule
+				}
+				n := 0
+				if ordinal := v[:len(v)-2]; ordinal != "" {
+					if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -53 || n > 53 {
+						return nil, ErrInvalidRule
+					}
+				}
+				r.ByDay = append(r.ByDay, WeekdayNum{Weekday: wd, N: n})
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				n, errN := strconv.Atoi(v)
+				if errN != nil || n == 0 || n < -31 || n > 31 {
+					return nil, ErrInvalidRule
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, v := range strings.Split(value, ",") {
+				n, errN := strconv.Atoi(v)
+				if errN != nil || n < 1 || n > 12 {
+					return nil, ErrInvalidRule
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			if _, ok := weekdays[value]; !ok {
+				return nil, ErrInvalidRule
+			}
+		default:
+			return nil, ErrInvalidRule
+		}
+	}
+	if r.Freq == "" {
+		return nil, ErrInvalidRule
+	}
+	return r, nil
+}
+
+// Matches reports whether the day of date is an occurrence of the rule.
+func (r *Rule) Matches(date time.Time) bool {
+	date = day(date)
+	if date.Before(r.Start) || (!r.Until.IsZero() && date.After(r.Until)) {
+		return false
+	}
+	if !r.matches(date) {
+		return false
+	}
+	if r.Count == 0 {
+		return true
+	}
+	occurrences := 0
+	for d := r.Start; !d.After(date); d = d.AddDate(0, 0, 1) {
+		if r.matches(d) {
+			occurrences++
+		}
+		if occurrences > r.Count {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Rule) matches(date time.Time) bool {
+	if !r.inInterval(date) {
+		return false
+	}
+	if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, date.Month()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(date) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !r.matchesDay(date) {
+		return false
+	}
+	if len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 {
+		return true
+	}
+	// without BYDAY nor BYMONTHDAY the rule repeats the day of its start
+	switch r.Freq {
+	case Weekly:
+		return date.Weekday() == r.Start.Weekday()
+	case Monthly:
+		return date.Day() == r.Start.Day()
+	case Yearly:
+		if len(r.ByMonth) > 0 {
+			return date.Day() == r.Start.Day()
+		}
+		return date.Day() == r.Start.Day() && date.Month() == r.Start.Month()
+	}
+	return true
+}
+
+func (r *Rule) inInterval(date time.Time) bool {
+	if r.Interval == 1 {
+		return true
+	}
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = daysBetween(r.Start, date)
+	case Weekly:
+		periods = daysBetween(weekStart(r.Start), weekStart(date)) / 7
+	case Monthly:
+		periods = (date.Year()-r.Start.Year())*12 + int(date.Month()) - int(r.Start.Month())
+	case Yearly:
+		periods = date.Year() - r.Start.Year()
+	}
+	return periods%r.Interval == 0
+}
+
+func (r *Rule) matchesMonthDay(date time.Time) bool {
+	last := daysInMonth(date)
+	for _, n := range r.ByMonthDay {
+		if n == date.Day() || (n < 0 && last+n+1 == date.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesDay(date time.Time) bool {
+	for _, wd := range r.ByDay {
+		if wd.Weekday != date.Weekday() {
+			continue
+		}
+		if wd.N == 0 || r.Freq == Daily || r.Freq == Weekly {
+			return true
+		}
+		var nth, fromEnd int
+		if r.Freq == Yearly && len(r.ByMonth) == 0 {
+			nth = (date.YearDay()-1)/7 + 1
+			fromEnd = -((daysInYear(date)-date.YearDay())/7 + 1)
+		} else {
+			nth = (date.Day()-1)/7 + 1
+			fromEnd = -((daysInMonth(date)-date.Day())/7 + 1)
+		}
+		if wd.N == nth || wd.N == fromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// weekStart returns the monday of the week of t, the default RFC 5545 WKST.
+func weekStart(t time.Time) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(t time.Time) int {
+	return time.Date(t.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse("2006/01/02", s)
+	return t
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr bool
+	}{
+		{name: "weekly by day", rule: "FREQ=WEEKLY;BYDAY=FR"},
+		{name: "with prefix", rule: "RRULE:FREQ=MONTHLY;BYDAY=1SU"},
+		{name: "last weekday", rule: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{name: "yearly", rule: "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25;UNTIL=20300101"},
+		{name: "no frequency", rule: "BYDAY=FR", wantErr: true},
+		{name: "unknown frequency", rule: "FREQ=HOURLY", wantErr: true},
+		{name: "wrong weekday", rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "wrong interval", rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "unknown part", rule: "FREQ=DAILY;BYHOUR=10", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.rule, date("2023/01/01"))
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		date  string
+		want  bool
+	}{
+		{name: "every friday", rule: "FREQ=WEEKLY;BYDAY=FR", start: "2023/06/01", date: "2023/06/09", want: true},
+		{name: "every friday, thursday", rule: "FREQ=WEEKLY;BYDAY=FR", start: "2023/06/01", date: "2023/06/08", want: false},
+		{name: "before start", rule: "FREQ=WEEKLY;BYDAY=FR", start: "2023/06/10", date: "2023/06/09", want: false},
+		{name: "weekly without byday", rule: "FREQ=WEEKLY", start: "2023/06/05", date: "2023/06/19", want: true},
+		{name: "every other week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", start: "2023/06/05", date: "2023/06/12", want: false},
+		{name: "every other week, next", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", start: "2023/06/05", date: "2023/06/19", want: true},
+		{name: "first sunday", rule: "FREQ=MONTHLY;BYDAY=1SU", start: "2023/01/01", date: "2023/07/02", want: true},
+		{name: "first sunday, second", rule: "FREQ=MONTHLY;BYDAY=1SU", start: "2023/01/01", date: "2023/07/09", want: false},
+		{name: "last friday", rule: "FREQ=MONTHLY;BYDAY=-1FR", start: "2023/01/01", date: "2023/06/30", want: true},
+		{name: "last day of month", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", start: "2023/01/01", date: "2024/02/29", want: true},
+		{name: "christmas", rule: "FREQ=YEARLY;BYMONTH=12;BYMONTHDAY=25", start: "2023/01/01", date: "2025/12/25", want: true},
+		{name: "until", rule: "FREQ=DAILY;UNTIL=20230610", start: "2023/06/01", date: "2023/06/11", want: false},
+		{name: "count", rule: "FREQ=WEEKLY;BYDAY=FR;COUNT=2", start: "2023/06/01", date: "2023/06/09", want: true},
+		{name: "count exceeded", rule: "FREQ=WEEKLY;BYDAY=FR;COUNT=2", start: "2023/06/01", date: "2023/06/16", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule, date(tt.start))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, r.Matches(date(tt.date)))
+		})
+	}
+}